@@ -3,6 +3,7 @@ package main
 import (
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/kubeshop/testkube-executor-ginkgo/pkg/runner"
 	"github.com/kubeshop/testkube/pkg/executor/agent"
@@ -15,5 +16,29 @@ func main() {
 		output.PrintError(os.Stderr, fmt.Errorf("could not initialize runner: %w", err))
 		os.Exit(1)
 	}
+
+	if len(os.Args) > 1 && os.Args[1] == "diagnose" {
+		diagnose(ginkgo, os.Args[2:])
+		return
+	}
+
 	agent.Run(ginkgo, os.Args)
 }
+
+// diagnose runs the pre-flight checks, params can be overridden with GinkgoParam=value arguments
+func diagnose(ginkgo *runner.GinkgoRunner, args []string) {
+	params := runner.InitializeGinkgoParams()
+	for _, arg := range args {
+		kv := strings.SplitN(arg, "=", 2)
+		if len(kv) != 2 {
+			output.PrintError(os.Stderr, fmt.Errorf("invalid param %q, expected GinkgoParam=value", arg))
+			os.Exit(1)
+		}
+		params[kv[0]] = kv[1]
+	}
+
+	if err := ginkgo.Diagnose(params).Err(); err != nil {
+		output.PrintError(os.Stderr, err)
+		os.Exit(1)
+	}
+}
@@ -4,6 +4,7 @@ go 1.18
 
 require (
 	github.com/kubeshop/testkube v1.9.31
+	github.com/minio/minio-go/v7 v7.0.47
 	github.com/stretchr/testify v1.8.1
 )
 
@@ -32,7 +33,6 @@ require (
 	github.com/kubeshop/testkube-operator v1.9.4 // indirect
 	github.com/mailru/easyjson v0.7.7 // indirect
 	github.com/minio/md5-simd v1.1.2 // indirect
-	github.com/minio/minio-go/v7 v7.0.47 // indirect
 	github.com/minio/sha256-simd v1.0.0 // indirect
 	github.com/modern-go/concurrent v0.0.0-20180306012644-bacd9c7ef1dd // indirect
 	github.com/modern-go/reflect2 v1.0.2 // indirect
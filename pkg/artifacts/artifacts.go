@@ -0,0 +1,59 @@
+// Package artifacts gives Ginkgo specs a place to write files which the executor uploads as artifacts
+package artifacts
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/onsi/ginkgo/v2"
+)
+
+// DirEnv is the environment variable with the artifacts directory of the execution
+const DirEnv = "TESTKUBE_ARTIFACTS_DIR"
+
+// maxNameLength keeps spec directory names within file system limits
+const maxNameLength = 120
+
+var unsafeChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// Dir returns the artifacts directory of the execution, outside of Testkube it falls back
+// to a directory in the system temp directory
+func Dir() string {
+	if dir := os.Getenv(DirEnv); dir != "" {
+		return dir
+	}
+
+	return filepath.Join(os.TempDir(), "testkube-artifacts")
+}
+
+// DirFor creates and returns a subdirectory of the artifacts directory named after name
+func DirFor(name string) (string, error) {
+	dir := filepath.Join(Dir(), DirName(name))
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return "", err
+	}
+
+	return dir, nil
+}
+
+// SpecDir creates and returns the artifacts subdirectory of the running spec, it must be called from within a spec.
+// Files keep the subdirectory when uploaded, so specs can write files with the same name.
+func SpecDir() (string, error) {
+	return DirFor(ginkgo.CurrentSpecReport().FullText())
+}
+
+// DirName turns a spec text into a safe directory name
+func DirName(name string) string {
+	name = strings.Trim(unsafeChars.ReplaceAllString(name, "_"), "_.")
+	if len(name) > maxNameLength {
+		name = name[:maxNameLength]
+	}
+
+	if name == "" {
+		return "spec"
+	}
+
+	return name
+}
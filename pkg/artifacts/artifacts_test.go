@@ -0,0 +1,35 @@
+package artifacts
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestArtifacts(t *testing.T) {
+	t.Run("Dir should return the directory exported by the executor", func(t *testing.T) {
+		t.Setenv(DirEnv, "/data/artifacts")
+		assert.Equal(t, "/data/artifacts", Dir())
+
+		t.Setenv(DirEnv, "")
+		assert.Equal(t, filepath.Join(os.TempDir(), "testkube-artifacts"), Dir())
+	})
+
+	t.Run("DirFor should create a subdirectory with a safe name", func(t *testing.T) {
+		root := t.TempDir()
+		t.Setenv(DirEnv, root)
+
+		dir, err := DirFor("API responds with 200 [smoke]")
+		assert.NoError(t, err)
+		assert.Equal(t, filepath.Join(root, "API_responds_with_200_smoke"), dir)
+		assert.DirExists(t, dir)
+	})
+
+	t.Run("DirName should limit length and never be empty", func(t *testing.T) {
+		assert.Equal(t, "spec", DirName("../.."))
+		assert.Len(t, DirName(strings.Repeat("a", 300)), maxNameLength)
+	})
+}
@@ -0,0 +1,177 @@
+package runner
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/kubeshop/testkube/pkg/api/v1/testkube"
+	"github.com/kubeshop/testkube/pkg/executor/output"
+	"github.com/kubeshop/testkube/pkg/ui"
+)
+
+// ArtifactsDirEnv is the environment variable exporting the artifacts directory of the execution to the specs
+const ArtifactsDirEnv = "TESTKUBE_ARTIFACTS_DIR"
+
+const (
+	// reportsArtifactsName keeps reports at the top level of the execution artifacts
+	reportsArtifactsName = ""
+	// specArtifactsName is the directory files written by the specs to the artifacts directory are uploaded to
+	specArtifactsName = "artifacts"
+	// requestedArtifactsName is the directory requested artifact directories and files are uploaded to
+	requestedArtifactsName = "files"
+)
+
+// ArtifactSource is a directory or a file scraped under Name, files in a directory are named after their path
+// relative to the directory within Name
+type ArtifactSource struct {
+	Path string
+	Name string
+}
+
+// ArtifactFile is a single file uploaded under Name, a slash separated path within the execution artifacts
+type ArtifactFile struct {
+	Source string
+	Name   string
+}
+
+// PrepareArtifactsDir creates the artifacts directory of the execution and exports it to the ginkgo process,
+// everything written there by the specs is scraped
+func PrepareArtifactsDir(dataDir, executionID string) (string, error) {
+	dir := filepath.Join(dataDir, "artifacts", executionID)
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return "", err
+	}
+
+	if err := os.Setenv(ArtifactsDirEnv, dir); err != nil {
+		return "", err
+	}
+
+	output.PrintLog(fmt.Sprintf("%s Artifacts directory %s exported as %s", ui.IconCheckMark, dir, ArtifactsDirEnv))
+	return dir, nil
+}
+
+// ArtifactPatterns returns artifact directories and glob patterns requested by the GinkgoArtifactDirs param
+// and the artifact request of the execution
+func ArtifactPatterns(execution testkube.Execution, param string) []string {
+	patterns := []string{}
+	for _, pattern := range strings.Split(param, ",") {
+		if pattern = strings.TrimSpace(pattern); pattern != "" {
+			patterns = append(patterns, pattern)
+		}
+	}
+
+	if execution.ArtifactRequest != nil {
+		patterns = append(patterns, execution.ArtifactRequest.Dirs...)
+	}
+
+	return patterns
+}
+
+// ArtifactPaths resolves artifact patterns relative to runPath into existing directories and files to scrape,
+// paths inside reportsPath are left out as reports are always scraped. They're named after their path relative
+// to runPath in the files directory, so files with the same name in different directories don't collide.
+func ArtifactPaths(runPath, reportsPath string, patterns []string) ([]ArtifactSource, error) {
+	sources := []ArtifactSource{}
+	seen := make(map[string]bool)
+	for _, pattern := range patterns {
+		if !filepath.IsAbs(pattern) {
+			pattern = filepath.Join(runPath, pattern)
+		}
+
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid artifact pattern %q: %w", pattern, err)
+		}
+
+		if len(matches) == 0 {
+			output.PrintLog(fmt.Sprintf("%s no artifacts found for %s", ui.IconWarning, pattern))
+		}
+
+		for _, match := range matches {
+			if seen[match] || isWithin(reportsPath, match) {
+				continue
+			}
+
+			if _, err := os.Stat(match); err != nil {
+				continue
+			}
+
+			name := strings.TrimPrefix(filepath.ToSlash(match), "/")
+			if rel, err := filepath.Rel(runPath, match); err == nil && isWithin(runPath, match) {
+				name = filepath.ToSlash(rel)
+			}
+
+			seen[match] = true
+			sources = append(sources, ArtifactSource{Path: match, Name: path.Join(requestedArtifactsName, name)})
+		}
+	}
+
+	return sources, nil
+}
+
+// ArtifactFiles lists files in sources with the names they're uploaded under, files in directories are named
+// after their path relative to the directory. Names are unique, a file named like one listed before is left out
+// with a warning, so no upload overwrites another one.
+func ArtifactFiles(sources []ArtifactSource) ([]ArtifactFile, error) {
+	files := []ArtifactFile{}
+	names := make(map[string]string)
+	for _, source := range sources {
+		info, err := os.Stat(source.Path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		base := source.Path
+		if !info.IsDir() {
+			base = filepath.Dir(source.Path)
+		}
+
+		err = filepath.WalkDir(source.Path, func(filePath string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+
+			if d.IsDir() {
+				return nil
+			}
+
+			name := source.Name
+			if info.IsDir() {
+				rel, err := filepath.Rel(base, filePath)
+				if err != nil {
+					return err
+				}
+				name = path.Join(source.Name, filepath.ToSlash(rel))
+			}
+
+			if previous, found := names[name]; found {
+				if previous != filePath {
+					output.PrintLog(fmt.Sprintf("%s artifact %s not uploaded: %s is already uploaded as %s", ui.IconWarning, filePath, previous, name))
+				}
+				return nil
+			}
+
+			names[name] = filePath
+			files = append(files, ArtifactFile{Source: filePath, Name: name})
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("could not list artifacts in %s: %w", source.Path, err)
+		}
+	}
+
+	return files, nil
+}
+
+// isWithin checks if path is dir or below it
+func isWithin(dir, path string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
@@ -0,0 +1,136 @@
+package runner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kubeshop/testkube-executor-ginkgo/pkg/artifacts"
+	"github.com/kubeshop/testkube/pkg/api/v1/testkube"
+	"github.com/kubeshop/testkube/pkg/envs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestArtifacts(t *testing.T) {
+	t.Run("PrepareArtifactsDir should create and export the execution artifacts directory", func(t *testing.T) {
+		dataDir := t.TempDir()
+		t.Setenv(ArtifactsDirEnv, "")
+
+		dir, err := PrepareArtifactsDir(dataDir, "64327a1f")
+		assert.NoError(t, err)
+		assert.Equal(t, filepath.Join(dataDir, "artifacts", "64327a1f"), dir)
+		assert.DirExists(t, dir)
+		assert.Equal(t, dir, os.Getenv(ArtifactsDirEnv))
+	})
+
+	t.Run("ArtifactPatterns should combine param and artifact request", func(t *testing.T) {
+		execution := testkube.Execution{ArtifactRequest: &testkube.ArtifactRequest{Dirs: []string{"dumps"}}}
+		assert.Equal(t, []string{"screenshots", "logs/*.har", "dumps"}, ArtifactPatterns(execution, "screenshots, logs/*.har,"))
+		assert.Empty(t, ArtifactPatterns(testkube.Execution{}, ""))
+	})
+
+	t.Run("ArtifactPaths should resolve directories and globs relative to run path", func(t *testing.T) {
+		runPath := t.TempDir()
+		reportsPath := filepath.Join(runPath, "reports")
+		for _, dir := range []string{"screenshots", "logs", "reports"} {
+			assert.NoError(t, os.Mkdir(filepath.Join(runPath, dir), 0755))
+		}
+		for _, file := range []string{"logs/api.har", "logs/web.har", "logs/run.log", "reports/report.xml"} {
+			assert.NoError(t, os.WriteFile(filepath.Join(runPath, file), []byte("artifact"), 0644))
+		}
+
+		sources, err := ArtifactPaths(runPath, reportsPath, []string{"screenshots", "logs/*.har", "missing", "reports/*", "screenshots"})
+		assert.NoError(t, err)
+		assert.Equal(t, []ArtifactSource{
+			{Path: filepath.Join(runPath, "screenshots"), Name: "files/screenshots"},
+			{Path: filepath.Join(runPath, "logs", "api.har"), Name: "files/logs/api.har"},
+			{Path: filepath.Join(runPath, "logs", "web.har"), Name: "files/logs/web.har"},
+		}, sources)
+	})
+
+	t.Run("ArtifactFiles should name files so that equal file names in different directories don't collide", func(t *testing.T) {
+		runPath := t.TempDir()
+		reportsPath := filepath.Join(runPath, "reports")
+		for _, dir := range []string{"screenshots", "logs", "reports"} {
+			assert.NoError(t, os.Mkdir(filepath.Join(runPath, dir), 0755))
+		}
+		for _, file := range []string{"screenshots/a.png", "logs/a.png", "reports/report.json"} {
+			assert.NoError(t, os.WriteFile(filepath.Join(runPath, file), []byte(file), 0644))
+		}
+
+		sources, err := ArtifactPaths(runPath, reportsPath, []string{"screenshots", "logs", "logs/*.png"})
+		assert.NoError(t, err)
+		files, err := ArtifactFiles(append([]ArtifactSource{{Path: reportsPath}}, sources...))
+		assert.NoError(t, err)
+		assert.Equal(t, []ArtifactFile{
+			{Source: filepath.Join(reportsPath, "report.json"), Name: "report.json"},
+			{Source: filepath.Join(runPath, "screenshots", "a.png"), Name: "files/screenshots/a.png"},
+			{Source: filepath.Join(runPath, "logs", "a.png"), Name: "files/logs/a.png"},
+		}, files)
+	})
+
+	t.Run("ArtifactPaths should fail on invalid patterns", func(t *testing.T) {
+		_, err := ArtifactPaths(t.TempDir(), "", []string{"logs/[.har"})
+		assert.Error(t, err)
+	})
+
+	t.Run("ScrapeArtifacts should scrape what's set up so far", func(t *testing.T) {
+		dataDir := t.TempDir()
+		root := t.TempDir()
+		artifactsPath := filepath.Join(dataDir, "artifacts", "64327a1f")
+		assert.NoError(t, os.MkdirAll(artifactsPath, 0755))
+		assert.NoError(t, os.WriteFile(filepath.Join(artifactsPath, "server.log"), []byte("log"), 0644))
+
+		r := &GinkgoRunner{Params: envs.Params{ScrapperEnabled: true, DataDir: dataDir}, Scraper: NewFilesystemScraper(root)}
+		err := r.ScrapeArtifacts(testkube.Execution{Id: "64327a1f"}, map[string]string{}, UploadOptions{}, "", "", artifactsPath)
+		assert.NoError(t, err)
+		assert.FileExists(t, filepath.Join(root, "64327a1f", "artifacts", "server.log"))
+	})
+	t.Run("ScrapeArtifacts should upload files with the same name written by different specs", func(t *testing.T) {
+		dataDir := t.TempDir()
+		root := t.TempDir()
+		artifactsPath, err := PrepareArtifactsDir(dataDir, "64327a1f")
+		assert.NoError(t, err)
+		for _, spec := range []string{"API renders the dashboard", "API renders the dashboard twice"} {
+			dir, err := artifacts.DirFor(spec)
+			assert.NoError(t, err)
+			assert.NoError(t, os.WriteFile(filepath.Join(dir, "dashboard.png"), []byte(spec), 0644))
+		}
+
+		r := &GinkgoRunner{Params: envs.Params{ScrapperEnabled: true, DataDir: dataDir}, Scraper: NewFilesystemScraper(root)}
+		assert.NoError(t, r.ScrapeArtifacts(testkube.Execution{Id: "64327a1f"}, map[string]string{}, UploadOptions{}, "", "", artifactsPath))
+
+		for _, spec := range []string{"API renders the dashboard", "API renders the dashboard twice"} {
+			data, err := os.ReadFile(filepath.Join(root, "64327a1f", "artifacts", artifacts.DirName(spec), "dashboard.png"))
+			assert.NoError(t, err)
+			assert.Equal(t, spec, string(data))
+		}
+	})
+
+	t.Run("ScrapeArtifacts should hand a scraper without file support a directory with the upload layout", func(t *testing.T) {
+		dataDir := t.TempDir()
+		artifactsPath := filepath.Join(dataDir, "artifacts", "64327a1f")
+		assert.NoError(t, os.MkdirAll(filepath.Join(artifactsPath, "logs"), 0755))
+		assert.NoError(t, os.WriteFile(filepath.Join(artifactsPath, "logs", "server.log"), []byte("log"), 0644))
+
+		legacy := &directoryScraper{}
+		r := &GinkgoRunner{Params: envs.Params{ScrapperEnabled: true, DataDir: dataDir}, Scraper: legacy}
+		assert.NoError(t, r.ScrapeArtifacts(testkube.Execution{Id: "64327a1f"}, map[string]string{}, UploadOptions{}, "", "", artifactsPath))
+
+		assert.Equal(t, "64327a1f", legacy.executionID)
+		assert.Len(t, legacy.directories, 1)
+		assert.FileExists(t, filepath.Join(legacy.directories[0], "artifacts", "logs", "server.log"))
+	})
+}
+
+// directoryScraper records what a scraper.Scraper like the testkube ones is asked to scrape
+type directoryScraper struct {
+	executionID string
+	directories []string
+}
+
+func (s *directoryScraper) Scrape(executionID string, directories []string) error {
+	s.executionID = executionID
+	s.directories = directories
+	return nil
+}
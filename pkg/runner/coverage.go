@@ -0,0 +1,336 @@
+package runner
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/kubeshop/testkube/pkg/executor/output"
+	"github.com/kubeshop/testkube/pkg/process"
+	"github.com/kubeshop/testkube/pkg/ui"
+)
+
+const (
+	// defaultCoverProfile is the profile name used by ginkgo when only --cover is set
+	defaultCoverProfile = "coverprofile.out"
+	coverageHTMLReport  = "coverage.html"
+)
+
+// CoverProfile is a parsed go cover profile, blocks are keyed by "file:startLine.startCol,endLine.endCol"
+type CoverProfile struct {
+	Mode   string
+	Blocks map[string]CoverBlock
+	order  []string
+}
+
+// CoverBlock is a single block of a cover profile
+type CoverBlock struct {
+	Statements int
+	Count      int
+}
+
+// PackageCoverage is the statement coverage of a single package
+type PackageCoverage struct {
+	Package    string
+	Statements int
+	Covered    int
+}
+
+// Percent returns covered statements in percent
+func (c PackageCoverage) Percent() float64 {
+	if c.Statements == 0 {
+		return 0
+	}
+
+	return float64(c.Covered) * 100 / float64(c.Statements)
+}
+
+// CoverageSummary is the total and per package statement coverage
+type CoverageSummary struct {
+	Total    PackageCoverage
+	Packages []PackageCoverage
+}
+
+// String renders the summary as appended to the execution output
+func (s CoverageSummary) String() string {
+	b := strings.Builder{}
+	b.WriteString(fmt.Sprintf("Coverage: %.1f%% of statements\n", s.Total.Percent()))
+	for _, pkg := range s.Packages {
+		b.WriteString(fmt.Sprintf("  %s: %.1f%% of statements\n", pkg.Package, pkg.Percent()))
+	}
+
+	return b.String()
+}
+
+// ParseCoverProfile reads a go cover profile
+func ParseCoverProfile(r io.Reader) (*CoverProfile, error) {
+	profile := &CoverProfile{Blocks: make(map[string]CoverBlock)}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		if strings.HasPrefix(line, "mode:") {
+			mode := strings.TrimSpace(strings.TrimPrefix(line, "mode:"))
+			if profile.Mode != "" && profile.Mode != mode {
+				return nil, fmt.Errorf("mixed cover modes %s and %s", profile.Mode, mode)
+			}
+			profile.Mode = mode
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("invalid cover profile line: %s", line)
+		}
+
+		statements, err := strconv.Atoi(fields[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid statements count in line %s: %w", line, err)
+		}
+
+		count, err := strconv.Atoi(fields[2])
+		if err != nil {
+			return nil, fmt.Errorf("invalid count in line %s: %w", line, err)
+		}
+
+		profile.add(fields[0], CoverBlock{Statements: statements, Count: count})
+	}
+
+	return profile, scanner.Err()
+}
+
+// Merge adds blocks of other profile, counts of the same block are summed up, in set mode a block is covered if it's covered in any profile
+func (p *CoverProfile) Merge(other *CoverProfile) error {
+	if p.Mode == "" {
+		p.Mode = other.Mode
+	}
+
+	if other.Mode != "" && p.Mode != other.Mode {
+		return fmt.Errorf("can't merge cover profiles with modes %s and %s", p.Mode, other.Mode)
+	}
+
+	for _, key := range other.order {
+		p.add(key, other.Blocks[key])
+	}
+
+	return nil
+}
+
+// WriteTo writes the profile in go cover profile format
+func (p *CoverProfile) WriteTo(w io.Writer) (int64, error) {
+	written := int64(0)
+	n, err := fmt.Fprintf(w, "mode: %s\n", p.Mode)
+	written += int64(n)
+	if err != nil {
+		return written, err
+	}
+
+	for _, key := range p.order {
+		block := p.Blocks[key]
+		n, err = fmt.Fprintf(w, "%s %d %d\n", key, block.Statements, block.Count)
+		written += int64(n)
+		if err != nil {
+			return written, err
+		}
+	}
+
+	return written, nil
+}
+
+// Summary returns the total and per package statement coverage
+func (p *CoverProfile) Summary() CoverageSummary {
+	summary := CoverageSummary{Total: PackageCoverage{Package: "total"}}
+	packages := make(map[string]*PackageCoverage)
+	for key, block := range p.Blocks {
+		file := key
+		if i := strings.LastIndex(key, ":"); i != -1 {
+			file = key[:i]
+		}
+
+		pkgName := path.Dir(file)
+		pkg, found := packages[pkgName]
+		if !found {
+			pkg = &PackageCoverage{Package: pkgName}
+			packages[pkgName] = pkg
+		}
+
+		pkg.Statements += block.Statements
+		summary.Total.Statements += block.Statements
+		if block.Count > 0 {
+			pkg.Covered += block.Statements
+			summary.Total.Covered += block.Statements
+		}
+	}
+
+	for _, pkg := range packages {
+		summary.Packages = append(summary.Packages, *pkg)
+	}
+	sort.Slice(summary.Packages, func(i, j int) bool {
+		return summary.Packages[i].Package < summary.Packages[j].Package
+	})
+
+	return summary
+}
+
+func (p *CoverProfile) add(key string, block CoverBlock) {
+	existing, found := p.Blocks[key]
+	if !found {
+		p.Blocks[key] = block
+		p.order = append(p.order, key)
+		return
+	}
+
+	if p.Mode == "set" {
+		if block.Count > existing.Count {
+			existing.Count = block.Count
+		}
+	} else {
+		existing.Count += block.Count
+	}
+	p.Blocks[key] = existing
+}
+
+// CoverProfileName returns the cover profile file name configured by GinkgoCover and GinkgoCoverProfile params
+func CoverProfileName(params map[string]string) string {
+	if params["GinkgoCoverProfile"] != "" {
+		return flagValue(params["GinkgoCoverProfile"])
+	}
+
+	if params["GinkgoCover"] != "" {
+		return defaultCoverProfile
+	}
+
+	return ""
+}
+
+// CollectCoverage merges all cover profiles named profileName found in runPath into reportsPath,
+// generates an HTML coverage report next to it and returns the coverage summary
+func CollectCoverage(runPath, reportsPath, profileName string) (*CoverageSummary, error) {
+	output.PrintLog(fmt.Sprintf("%s Collecting coverage profiles", ui.IconWorld))
+
+	profiles := []string{}
+	err := filepath.WalkDir(runPath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() && path == reportsPath {
+			return filepath.SkipDir
+		}
+
+		if !d.IsDir() && d.Name() == profileName {
+			profiles = append(profiles, path)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not look up cover profiles: %w", err)
+	}
+
+	if len(profiles) == 0 {
+		return nil, fmt.Errorf("could not find any cover profile named %s", profileName)
+	}
+
+	merged := &CoverProfile{Blocks: make(map[string]CoverBlock)}
+	for _, profilePath := range profiles {
+		file, err := os.Open(profilePath)
+		if err != nil {
+			return nil, err
+		}
+
+		profile, err := ParseCoverProfile(file)
+		file.Close()
+		if err != nil {
+			return nil, fmt.Errorf("could not parse cover profile %s: %w", profilePath, err)
+		}
+
+		if err = merged.Merge(profile); err != nil {
+			return nil, err
+		}
+
+		if err = os.Remove(profilePath); err != nil {
+			return nil, err
+		}
+	}
+
+	mergedPath := filepath.Join(reportsPath, profileName)
+	file, err := os.Create(mergedPath)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = merged.WriteTo(file)
+	file.Close()
+	if err != nil {
+		return nil, fmt.Errorf("could not write merged cover profile: %w", err)
+	}
+
+	// HTML report needs the sources of the module, it's optional so don't fail on it
+	_, err = process.ExecuteInDir(runPath, "go", "tool", "cover", "-html="+mergedPath, "-o", filepath.Join(reportsPath, coverageHTMLReport))
+	if err != nil {
+		output.PrintLog(fmt.Sprintf("%s could not generate HTML coverage report: %s", ui.IconWarning, err.Error()))
+	}
+
+	summary := merged.Summary()
+	output.PrintLog(fmt.Sprintf("%s Coverage collected from %d profiles: %.1f%% of statements", ui.IconCheckMark, len(profiles), summary.Total.Percent()))
+	return &summary, nil
+}
+
+// CheckCoverageThreshold fails when total or any package statement coverage is below the given percentages,
+// negative thresholds are not checked
+func CheckCoverageThreshold(summary *CoverageSummary, total, perPackage float64) error {
+	if total < 0 && perPackage < 0 {
+		return nil
+	}
+
+	if summary == nil {
+		return fmt.Errorf("coverage threshold is set but no coverage was collected")
+	}
+
+	problems := []string{}
+	if total >= 0 && summary.Total.Percent() < total {
+		problems = append(problems, fmt.Sprintf("total coverage %.1f%% is below %.1f%%", summary.Total.Percent(), total))
+	}
+
+	if perPackage >= 0 {
+		offending := []string{}
+		for _, pkg := range summary.Packages {
+			if pkg.Percent() < perPackage {
+				offending = append(offending, fmt.Sprintf("%s (%.1f%%)", pkg.Package, pkg.Percent()))
+			}
+		}
+
+		if len(offending) > 0 {
+			problems = append(problems, fmt.Sprintf("coverage of packages is below %.1f%%: %s", perPackage, strings.Join(offending, ", ")))
+		}
+	}
+
+	if len(problems) > 0 {
+		output.PrintLog(fmt.Sprintf("%s Coverage threshold not met: %s", ui.IconCross, strings.Join(problems, "; ")))
+		return fmt.Errorf("coverage threshold not met: %s", strings.Join(problems, "; "))
+	}
+
+	output.PrintLog(fmt.Sprintf("%s Coverage threshold met", ui.IconCheckMark))
+	return nil
+}
+
+// flagValue returns the value of a "--flag value" or "--flag=value" param
+func flagValue(param string) string {
+	param = strings.TrimSpace(param)
+	if i := strings.IndexAny(param, " ="); i != -1 {
+		return strings.TrimSpace(param[i+1:])
+	}
+
+	return ""
+}
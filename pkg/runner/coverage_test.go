@@ -0,0 +1,101 @@
+package runner
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const coverProfileOne = `mode: set
+example.com/suite/api/client.go:10.2,12.3 2 1
+example.com/suite/api/client.go:14.2,16.3 3 0
+example.com/suite/db/db.go:5.2,8.3 5 0
+`
+
+const coverProfileTwo = `mode: set
+example.com/suite/api/client.go:14.2,16.3 3 1
+example.com/suite/db/db.go:5.2,8.3 5 0
+`
+
+func TestCoverage(t *testing.T) {
+	t.Run("CoverProfile should merge blocks and summarise coverage per package", func(t *testing.T) {
+		merged, err := ParseCoverProfile(strings.NewReader(coverProfileOne))
+		assert.NoError(t, err)
+		other, err := ParseCoverProfile(strings.NewReader(coverProfileTwo))
+		assert.NoError(t, err)
+		assert.NoError(t, merged.Merge(other))
+
+		summary := merged.Summary()
+		assert.Equal(t, 10, summary.Total.Statements)
+		assert.Equal(t, 5, summary.Total.Covered)
+		assert.Equal(t, 50.0, summary.Total.Percent())
+		assert.Equal(t, []PackageCoverage{
+			{Package: "example.com/suite/api", Statements: 5, Covered: 5},
+			{Package: "example.com/suite/db", Statements: 5, Covered: 0},
+		}, summary.Packages)
+
+		b := bytes.Buffer{}
+		_, err = merged.WriteTo(&b)
+		assert.NoError(t, err)
+		assert.Equal(t, `mode: set
+example.com/suite/api/client.go:10.2,12.3 2 1
+example.com/suite/api/client.go:14.2,16.3 3 1
+example.com/suite/db/db.go:5.2,8.3 5 0
+`, b.String())
+	})
+
+	t.Run("CoverProfile should refuse merging different modes", func(t *testing.T) {
+		profile, err := ParseCoverProfile(strings.NewReader(coverProfileOne))
+		assert.NoError(t, err)
+		other, err := ParseCoverProfile(strings.NewReader("mode: atomic\n"))
+		assert.NoError(t, err)
+		assert.Error(t, profile.Merge(other))
+	})
+
+	t.Run("CoverProfileName should read profile name from params", func(t *testing.T) {
+		assert.Equal(t, "", CoverProfileName(map[string]string{}))
+		assert.Equal(t, "coverprofile.out", CoverProfileName(map[string]string{"GinkgoCover": "--cover"}))
+		assert.Equal(t, "cover.profile", CoverProfileName(map[string]string{"GinkgoCoverProfile": "--coverprofile cover.profile"}))
+		assert.Equal(t, "cover.out", CoverProfileName(map[string]string{"GinkgoCoverProfile": "--coverprofile=cover.out"}))
+	})
+
+	t.Run("CollectCoverage should merge profiles found in run path into reports", func(t *testing.T) {
+		runPath := t.TempDir()
+		reportsPath := filepath.Join(runPath, "reports")
+		assert.NoError(t, os.MkdirAll(filepath.Join(runPath, "api"), os.ModePerm))
+		assert.NoError(t, os.MkdirAll(reportsPath, os.ModePerm))
+		assert.NoError(t, os.WriteFile(filepath.Join(runPath, "cover.profile"), []byte(coverProfileOne), 0644))
+		assert.NoError(t, os.WriteFile(filepath.Join(runPath, "api", "cover.profile"), []byte(coverProfileTwo), 0644))
+
+		summary, err := CollectCoverage(runPath, reportsPath, "cover.profile")
+		assert.NoError(t, err)
+		assert.Equal(t, 50.0, summary.Total.Percent())
+		assert.FileExists(t, filepath.Join(reportsPath, "cover.profile"))
+		assert.NoFileExists(t, filepath.Join(runPath, "cover.profile"))
+		assert.Contains(t, summary.String(), "example.com/suite/db: 0.0% of statements")
+	})
+
+	t.Run("CheckCoverageThreshold should list packages below the threshold", func(t *testing.T) {
+		summary := &CoverageSummary{
+			Total: PackageCoverage{Package: "total", Statements: 10, Covered: 5},
+			Packages: []PackageCoverage{
+				{Package: "example.com/suite/api", Statements: 5, Covered: 5},
+				{Package: "example.com/suite/db", Statements: 5, Covered: 0},
+			},
+		}
+
+		assert.NoError(t, CheckCoverageThreshold(summary, -1, -1))
+		assert.NoError(t, CheckCoverageThreshold(summary, 50, -1))
+
+		err := CheckCoverageThreshold(summary, 80, 60)
+		assert.ErrorContains(t, err, "total coverage 50.0% is below 80.0%")
+		assert.ErrorContains(t, err, "example.com/suite/db (0.0%)")
+		assert.NotContains(t, err.Error(), "example.com/suite/api")
+
+		assert.ErrorContains(t, CheckCoverageThreshold(nil, 80, -1), "no coverage was collected")
+	})
+}
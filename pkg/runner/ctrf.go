@@ -0,0 +1,142 @@
+package runner
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+
+	"github.com/onsi/ginkgo/v2/types"
+)
+
+// CtrfReport is a report in the Common Test Report Format, see https://ctrf.io
+type CtrfReport struct {
+	Results CtrfResults `json:"results"`
+}
+
+// CtrfResults are the results of a single tool run
+type CtrfResults struct {
+	Tool    CtrfTool    `json:"tool"`
+	Summary CtrfSummary `json:"summary"`
+	Tests   []CtrfTest  `json:"tests"`
+}
+
+// CtrfTool is the tool which produced the results
+type CtrfTool struct {
+	Name string `json:"name"`
+}
+
+// CtrfSummary are totals of the results, start and stop are unix times in milliseconds
+type CtrfSummary struct {
+	Tests   int   `json:"tests"`
+	Passed  int   `json:"passed"`
+	Failed  int   `json:"failed"`
+	Pending int   `json:"pending"`
+	Skipped int   `json:"skipped"`
+	Other   int   `json:"other"`
+	Start   int64 `json:"start"`
+	Stop    int64 `json:"stop"`
+}
+
+// CtrfTest is a single spec, durations are in milliseconds
+type CtrfTest struct {
+	Name     string   `json:"name"`
+	Status   string   `json:"status"`
+	Duration int64    `json:"duration"`
+	Start    int64    `json:"start,omitempty"`
+	Stop     int64    `json:"stop,omitempty"`
+	Suite    string   `json:"suite,omitempty"`
+	Message  string   `json:"message,omitempty"`
+	Trace    string   `json:"trace,omitempty"`
+	FilePath string   `json:"filePath,omitempty"`
+	Line     int      `json:"line,omitempty"`
+	Retries  int      `json:"retries,omitempty"`
+	Flaky    bool     `json:"flaky,omitempty"`
+	Tags     []string `json:"tags,omitempty"`
+}
+
+// CtrfStatus maps a spec state to a CTRF test status
+func CtrfStatus(state types.SpecState) string {
+	switch {
+	case state == types.SpecStatePassed:
+		return "passed"
+	case state.Is(types.SpecStateFailureStates):
+		return "failed"
+	case state == types.SpecStateSkipped:
+		return "skipped"
+	case state == types.SpecStatePending:
+		return "pending"
+	default:
+		return "other"
+	}
+}
+
+// NewCtrfReport converts ginkgo reports to CTRF, specs and failed suite level nodes are reported as tests
+func NewCtrfReport(reports []types.Report) CtrfReport {
+	results := CtrfResults{Tool: CtrfTool{Name: "ginkgo"}, Tests: []CtrfTest{}}
+	for _, report := range reports {
+		if start := report.StartTime.UnixMilli(); !report.StartTime.IsZero() && (results.Summary.Start == 0 || start < results.Summary.Start) {
+			results.Summary.Start = start
+		}
+		if stop := report.EndTime.UnixMilli(); !report.EndTime.IsZero() && stop > results.Summary.Stop {
+			results.Summary.Stop = stop
+		}
+
+		for _, spec := range report.SpecReports {
+			if spec.LeafNodeType != types.NodeTypeIt && !spec.Failed() {
+				continue
+			}
+
+			test := CtrfTest{
+				Name:     strings.TrimSpace(spec.FullText()),
+				Status:   CtrfStatus(spec.State),
+				Duration: spec.RunTime.Milliseconds(),
+				Suite:    report.SuiteDescription,
+				FilePath: spec.LeafNodeLocation.FileName,
+				Line:     spec.LeafNodeLocation.LineNumber,
+				Tags:     spec.Labels(),
+			}
+			if spec.LeafNodeType != types.NodeTypeIt {
+				test.Name = strings.TrimSpace("[" + spec.LeafNodeType.String() + "] " + test.Name)
+			}
+			if !spec.StartTime.IsZero() {
+				test.Start = spec.StartTime.UnixMilli()
+				test.Stop = spec.EndTime.UnixMilli()
+			}
+			if spec.NumAttempts > 1 {
+				test.Retries = spec.NumAttempts - 1
+				test.Flaky = spec.State == types.SpecStatePassed
+			}
+			if spec.Failed() {
+				test.Message = strings.TrimSpace(spec.Failure.Message)
+				test.Trace = strings.TrimSpace(spec.Failure.Location.String() + "\n" + spec.Failure.Location.FullStackTrace)
+			}
+
+			switch test.Status {
+			case "passed":
+				results.Summary.Passed++
+			case "failed":
+				results.Summary.Failed++
+			case "skipped":
+				results.Summary.Skipped++
+			case "pending":
+				results.Summary.Pending++
+			default:
+				results.Summary.Other++
+			}
+			results.Tests = append(results.Tests, test)
+		}
+	}
+	results.Summary.Tests = len(results.Tests)
+
+	return CtrfReport{Results: results}
+}
+
+// WriteCtrfReport writes the CTRF report of the ginkgo reports to path
+func WriteCtrfReport(reports []types.Report, path string) error {
+	data, err := json.MarshalIndent(NewCtrfReport(reports), "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, 0644)
+}
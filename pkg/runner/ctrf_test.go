@@ -0,0 +1,70 @@
+package runner
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/onsi/ginkgo/v2/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCtrf(t *testing.T) {
+	start := time.Date(2023, 4, 1, 10, 0, 0, 0, time.UTC)
+	failed := newSpecReport([]string{"API"}, "returns 500", types.SpecStateFailed, "network")
+	failed.Failure = types.Failure{
+		Message:  "Expected 200 to equal 500",
+		Location: types.CodeLocation{FileName: "/data/repo/e2e/url_test.go", LineNumber: 21},
+	}
+	flaky := newSpecReport([]string{"API"}, "responds", types.SpecStatePassed)
+	flaky.NumAttempts = 2
+
+	reports := []types.Report{{
+		SuiteDescription: "E2E Suite",
+		StartTime:        start,
+		EndTime:          start.Add(5 * time.Second),
+		SpecReports: types.SpecReports{
+			{LeafNodeType: types.NodeTypeBeforeSuite, State: types.SpecStatePassed},
+			flaky,
+			failed,
+			newSpecReport([]string{"API"}, "is pending", types.SpecStatePending),
+		},
+	}}
+
+	t.Run("CtrfStatus should map spec states", func(t *testing.T) {
+		assert.Equal(t, "passed", CtrfStatus(types.SpecStatePassed))
+		assert.Equal(t, "failed", CtrfStatus(types.SpecStatePanicked))
+		assert.Equal(t, "failed", CtrfStatus(types.SpecStateTimedout))
+		assert.Equal(t, "skipped", CtrfStatus(types.SpecStateSkipped))
+		assert.Equal(t, "pending", CtrfStatus(types.SpecStatePending))
+		assert.Equal(t, "other", CtrfStatus(types.SpecStateInvalid))
+	})
+
+	t.Run("NewCtrfReport should convert specs to tests with summary", func(t *testing.T) {
+		report := NewCtrfReport(reports)
+		assert.Equal(t, "ginkgo", report.Results.Tool.Name)
+		assert.Equal(t, CtrfSummary{Tests: 3, Passed: 1, Failed: 1, Pending: 1, Start: start.UnixMilli(), Stop: start.Add(5 * time.Second).UnixMilli()}, report.Results.Summary)
+
+		assert.Equal(t, CtrfTest{
+			Name: "API responds", Status: "passed", Duration: 2000, Start: start.UnixMilli(), Stop: start.Add(2 * time.Second).UnixMilli(),
+			Suite: "E2E Suite", FilePath: "/data/repo/e2e/url_test.go", Line: 14, Retries: 1, Flaky: true, Tags: []string{},
+		}, report.Results.Tests[0])
+		assert.Equal(t, "failed", report.Results.Tests[1].Status)
+		assert.Equal(t, "Expected 200 to equal 500", report.Results.Tests[1].Message)
+		assert.Equal(t, "/data/repo/e2e/url_test.go:21", report.Results.Tests[1].Trace)
+		assert.Equal(t, []string{"network"}, report.Results.Tests[1].Tags)
+	})
+
+	t.Run("WriteCtrfReport should write the report as JSON", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "ctrf-report.json")
+		assert.NoError(t, WriteCtrfReport(reports, path))
+
+		data, err := os.ReadFile(path)
+		assert.NoError(t, err)
+		report := CtrfReport{}
+		assert.NoError(t, json.Unmarshal(data, &report))
+		assert.Len(t, report.Results.Tests, 3)
+	})
+}
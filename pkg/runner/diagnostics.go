@@ -0,0 +1,171 @@
+package runner
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/kubeshop/testkube/pkg/executor/output"
+	"github.com/kubeshop/testkube/pkg/process"
+	"github.com/kubeshop/testkube/pkg/ui"
+)
+
+// DiagnosticCheck is the outcome of a single pre-flight check
+type DiagnosticCheck struct {
+	Name    string
+	Detail  string
+	Warning string
+	Err     error
+}
+
+// Diagnostics is the outcome of all pre-flight checks
+type Diagnostics []DiagnosticCheck
+
+// Err returns an error listing all failed checks or nil if all of them passed
+func (d Diagnostics) Err() error {
+	problems := []string{}
+	for _, check := range d {
+		if check.Err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %s", check.Name, check.Err.Error()))
+		}
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+
+	return fmt.Errorf("pre-flight checks failed:\n - %s", strings.Join(problems, "\n - "))
+}
+
+// Print logs the outcome of every check
+func (d Diagnostics) Print() {
+	for _, check := range d {
+		switch {
+		case check.Err != nil:
+			output.PrintLog(fmt.Sprintf("%s %s: %s", ui.IconCross, check.Name, check.Err.Error()))
+		case check.Warning != "":
+			output.PrintLog(fmt.Sprintf("%s %s: %s, %s", ui.IconWarning, check.Name, check.Detail, check.Warning))
+		default:
+			output.PrintLog(fmt.Sprintf("%s %s: %s", ui.IconCheckMark, check.Name, check.Detail))
+		}
+	}
+}
+
+// Diagnose verifies the executor environment can run ginkgo with the given params and reports all problems at once.
+// The bundled ginkgo is required when it's requested or is the fallback of the auto mode, the version matching
+// the suite is checked by CheckGinkgoVersion once go.mod is fetched.
+func (r *GinkgoRunner) Diagnose(params map[string]string) Diagnostics {
+	output.PrintLog(fmt.Sprintf("%s Running pre-flight checks", ui.IconMicroscope))
+
+	diagnostics := Diagnostics{
+		checkCommand("go", "go", "version"),
+		checkCommand("git", "git", "--version"),
+		checkWritableDir(r.Params.DataDir),
+	}
+
+	if version := params["GinkgoVersion"]; version == "" || version == ginkgoVersionBundle || version == ginkgoVersionAuto {
+		diagnostics = append(diagnostics, checkCommand("ginkgo", ginkgoBin, "version"))
+	}
+
+	if params["GinkgoRace"] != "" {
+		diagnostics = append(diagnostics, checkCgo())
+	}
+
+	diagnostics.Print()
+	return diagnostics
+}
+
+// CheckGinkgoVersion verifies the resolved ginkgo binary runs, a version not matching the Ginkgo version required
+// in the go.mod of the suite is a warning, e.g. when a matching CLI couldn't be installed and the bundled one is used
+func CheckGinkgoVersion(bin, runPath, root string) DiagnosticCheck {
+	check := checkCommand("ginkgo", bin, "version")
+	if check.Err != nil {
+		return check
+	}
+
+	moduleVersion, err := GinkgoModuleVersion(runPath, root)
+	if err != nil {
+		// without the requirement there is nothing to match
+		return check
+	}
+	moduleVersion = "v" + strings.TrimPrefix(moduleVersion, "v")
+
+	cliVersion, err := GinkgoCLIVersion(bin)
+	if err != nil {
+		check.Err = fmt.Errorf("could not get %s version: %w", bin, err)
+		return check
+	}
+
+	if cliVersion != moduleVersion {
+		check.Warning = fmt.Sprintf("does not match %s %s required in go.mod, install ginkgo-%s in the executor image "+
+			"or make it installable with go install", ginkgoModule, moduleVersion, moduleVersion)
+	}
+
+	return check
+}
+
+func checkCommand(name, command string, args ...string) DiagnosticCheck {
+	check := DiagnosticCheck{Name: name}
+	if _, err := exec.LookPath(command); err != nil {
+		check.Err = fmt.Errorf("%s not found in PATH", command)
+		return check
+	}
+
+	out, err := process.Execute(command, args...)
+	if err != nil {
+		check.Err = fmt.Errorf("could not get %s version: %w", command, err)
+		return check
+	}
+
+	check.Detail = strings.TrimSpace(string(out))
+	return check
+}
+
+func checkWritableDir(dir string) DiagnosticCheck {
+	check := DiagnosticCheck{Name: "data directory"}
+	if dir == "" {
+		check.Detail = "not set, using working directory"
+		return check
+	}
+
+	file, err := os.CreateTemp(dir, "preflight")
+	if err != nil {
+		check.Err = fmt.Errorf("%s is not writable: %w", dir, err)
+		return check
+	}
+	file.Close()
+	os.Remove(file.Name())
+
+	check.Detail = fmt.Sprintf("%s is writable", dir)
+	return check
+}
+
+// checkCgo verifies a C compiler is available and cgo is enabled, both are required by the race detector
+func checkCgo() DiagnosticCheck {
+	check := DiagnosticCheck{Name: "cgo (required by --race)"}
+	out, err := process.Execute("go", "env", "CGO_ENABLED", "CC")
+	if err != nil {
+		check.Err = fmt.Errorf("could not read go env: %w", err)
+		return check
+	}
+
+	env := strings.Fields(string(out))
+	if len(env) < 2 {
+		check.Err = fmt.Errorf("unexpected go env output: %s", out)
+		return check
+	}
+
+	if env[0] != "1" {
+		check.Err = fmt.Errorf("CGO_ENABLED is %q, the race detector needs CGO_ENABLED=1", env[0])
+		return check
+	}
+
+	if _, err := exec.LookPath(env[1]); err != nil {
+		check.Err = fmt.Errorf("C compiler %s not found in PATH", env[1])
+		return check
+	}
+
+	check.Detail = fmt.Sprintf("enabled, using %s", env[1])
+	return check
+}
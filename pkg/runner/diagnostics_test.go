@@ -0,0 +1,80 @@
+package runner
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiagnostics(t *testing.T) {
+	t.Run("Diagnostics.Err should list all failed checks", func(t *testing.T) {
+		diagnostics := Diagnostics{
+			{Name: "go", Detail: "go version go1.18"},
+			{Name: "ginkgo", Err: errors.New("ginkgo not found in PATH")},
+			{Name: "git", Err: errors.New("git not found in PATH")},
+		}
+
+		err := diagnostics.Err()
+		assert.ErrorContains(t, err, "ginkgo: ginkgo not found in PATH")
+		assert.ErrorContains(t, err, "git: git not found in PATH")
+		assert.NotContains(t, err.Error(), "go version")
+	})
+
+	t.Run("Diagnostics.Err should be nil when all checks passed", func(t *testing.T) {
+		assert.NoError(t, Diagnostics{{Name: "go", Detail: "go version go1.18"}}.Err())
+	})
+
+	t.Run("checkCommand should fail for missing commands", func(t *testing.T) {
+		check := checkCommand("missing", "testkube-missing-command", "version")
+		assert.ErrorContains(t, check.Err, "not found in PATH")
+	})
+
+	t.Run("checkWritableDir should detect unwritable data directory", func(t *testing.T) {
+		assert.NoError(t, checkWritableDir(t.TempDir()).Err)
+		assert.Error(t, checkWritableDir(filepath.Join(t.TempDir(), "missing")).Err)
+	})
+	t.Run("Diagnose should require the bundled ginkgo unless a version is pinned", func(t *testing.T) {
+		r := &GinkgoRunner{}
+		names := func(diagnostics Diagnostics) []string {
+			result := []string{}
+			for _, check := range diagnostics {
+				result = append(result, check.Name)
+			}
+			return result
+		}
+
+		assert.Contains(t, names(r.Diagnose(map[string]string{"GinkgoVersion": "bundled"})), "ginkgo")
+		assert.Contains(t, names(r.Diagnose(map[string]string{"GinkgoVersion": "auto"})), "ginkgo")
+		assert.NotContains(t, names(r.Diagnose(map[string]string{"GinkgoVersion": "v2.9.2"})), "ginkgo")
+	})
+
+	t.Run("CheckGinkgoVersion should warn about a CLI not matching go.mod", func(t *testing.T) {
+		if runtime.GOOS == "windows" {
+			t.Skip("fake ginkgo binary is a shell script")
+		}
+
+		root := t.TempDir()
+		assert.NoError(t, os.WriteFile(filepath.Join(root, "go.mod"), []byte(goModWithGinkgo), 0644))
+		bin := filepath.Join(root, "ginkgo")
+		assert.NoError(t, os.WriteFile(bin, []byte("#!/bin/sh\necho Ginkgo Version 2.9.0\n"), 0755))
+
+		check := CheckGinkgoVersion(bin, root, root)
+		assert.NoError(t, check.Err)
+		assert.Equal(t, "Ginkgo Version 2.9.0", check.Detail)
+		assert.Contains(t, check.Warning, "does not match github.com/onsi/ginkgo/v2 v2.9.2 required in go.mod")
+
+		assert.NoError(t, os.WriteFile(bin, []byte("#!/bin/sh\necho Ginkgo Version 2.9.2\n"), 0755))
+		check = CheckGinkgoVersion(bin, root, root)
+		assert.NoError(t, check.Err)
+		assert.Empty(t, check.Warning)
+	})
+
+	t.Run("CheckGinkgoVersion should fail for a missing binary", func(t *testing.T) {
+		check := CheckGinkgoVersion("testkube-missing-ginkgo", t.TempDir(), t.TempDir())
+		assert.ErrorContains(t, check.Err, "not found in PATH")
+	})
+}
@@ -0,0 +1,60 @@
+package runner
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/kubeshop/testkube/pkg/api/v1/testkube"
+	"github.com/onsi/ginkgo/v2/types"
+)
+
+// MapDryRunToExecutionResults maps specs selected by a ginkgo --dry-run to execution steps and lists
+// packages, specs and labels in the output, specs filtered out are left out
+func MapDryRunToExecutionResults(out []byte, reports []types.Report, runPath string) (result testkube.ExecutionResult) {
+	result.Success()
+	result.OutputType = "text/plain"
+
+	summary := strings.Builder{}
+	labels := make(map[string]bool)
+	total := 0
+	for _, report := range reports {
+		pkg := report.SuitePath
+		if rel, err := filepath.Rel(runPath, report.SuitePath); err == nil && !strings.HasPrefix(rel, "..") {
+			pkg = rel
+		}
+
+		specs := []string{}
+		for _, spec := range report.SpecReports {
+			if spec.LeafNodeType != types.NodeTypeIt || spec.State != types.SpecStatePassed {
+				continue
+			}
+
+			result.Steps = append(result.Steps, testkube.ExecutionStepResult{
+				Name:   SpecStepName(report, spec),
+				Status: string(testkube.PASSED_ExecutionStatus),
+			})
+			specs = append(specs, JunitTestName(spec))
+			for _, label := range spec.Labels() {
+				labels[label] = true
+			}
+		}
+
+		total += len(specs)
+		summary.WriteString(fmt.Sprintf("%s (%s): %d specs\n", pkg, report.SuiteDescription, len(specs)))
+		for _, spec := range specs {
+			summary.WriteString(fmt.Sprintf("  %s\n", spec))
+		}
+	}
+
+	labelList := []string{}
+	for label := range labels {
+		labelList = append(labelList, label)
+	}
+	sort.Strings(labelList)
+
+	result.Output = fmt.Sprintf("%s\nDry run: %d specs in %d packages would run\n%sLabels: %s\n",
+		out, total, len(reports), summary.String(), strings.Join(labelList, ", "))
+	return result
+}
@@ -0,0 +1,32 @@
+package runner
+
+import (
+	"testing"
+
+	"github.com/kubeshop/testkube/pkg/api/v1/testkube"
+	"github.com/onsi/ginkgo/v2/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDryRun(t *testing.T) {
+	t.Run("MapDryRunToExecutionResults should list selected specs, packages and labels", func(t *testing.T) {
+		reports := []types.Report{{
+			SuitePath:        "/data/repo/e2e",
+			SuiteDescription: "E2E Suite",
+			SpecReports: types.SpecReports{
+				{LeafNodeType: types.NodeTypeBeforeSuite, State: types.SpecStatePassed},
+				newSpecReport([]string{"API"}, "responds", types.SpecStatePassed, "smoke"),
+				newSpecReport([]string{"API"}, "is slow", types.SpecStateSkipped, "slow"),
+			},
+		}}
+
+		result := MapDryRunToExecutionResults([]byte("Ran 1 of 2 Specs"), reports, "/data/repo")
+		assert.Equal(t, testkube.ExecutionStatusPassed, result.Status)
+		assert.Equal(t, []testkube.ExecutionStepResult{
+			{Name: "E2E Suite - [It] API responds [smoke]", Status: "passed"},
+		}, result.Steps)
+		assert.Contains(t, result.Output, "Dry run: 1 specs in 1 packages would run")
+		assert.Contains(t, result.Output, "e2e (E2E Suite): 1 specs\n  [It] API responds [smoke]\n")
+		assert.Contains(t, result.Output, "Labels: smoke\n")
+	})
+}
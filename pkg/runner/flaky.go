@@ -0,0 +1,72 @@
+package runner
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kubeshop/testkube/pkg/api/v1/testkube"
+	"github.com/kubeshop/testkube/pkg/executor/output"
+	"github.com/kubeshop/testkube/pkg/ui"
+	"github.com/onsi/ginkgo/v2/types"
+)
+
+// FlakySpec is a spec which passed only after being retried
+type FlakySpec struct {
+	Step     string
+	Attempts int
+}
+
+// FindFlakySpecs returns specs which passed after more than one attempt
+func FindFlakySpecs(reports []types.Report) []FlakySpec {
+	flaky := []FlakySpec{}
+	for _, report := range reports {
+		for _, spec := range report.SpecReports {
+			if spec.State == types.SpecStatePassed && spec.NumAttempts > 1 {
+				flaky = append(flaky, FlakySpec{Step: SpecStepName(report, spec), Attempts: spec.NumAttempts})
+			}
+		}
+	}
+
+	return flaky
+}
+
+// MarkFlakySpecs adds a flaky assertion to the steps of flaky specs
+func MarkFlakySpecs(result *testkube.ExecutionResult, flaky []FlakySpec) {
+	for _, spec := range flaky {
+		step := FindStep(result, spec.Step)
+		if step == nil {
+			continue
+		}
+
+		step.AssertionResults = append(step.AssertionResults, testkube.AssertionResult{
+			Name:         "flaky",
+			Status:       string(testkube.PASSED_ExecutionStatus),
+			ErrorMessage: fmt.Sprintf("passed after %d attempts", spec.Attempts),
+		})
+	}
+}
+
+// FlakySpecsSummary renders flaky specs as appended to the execution output
+func FlakySpecsSummary(flaky []FlakySpec) string {
+	b := strings.Builder{}
+	b.WriteString(fmt.Sprintf("Flaky specs: %d\n", len(flaky)))
+	for _, spec := range flaky {
+		b.WriteString(fmt.Sprintf("  %s (passed after %d attempts)\n", spec.Step, spec.Attempts))
+	}
+
+	return b.String()
+}
+
+// CheckFlakyThreshold fails when there are more flaky specs than the threshold allows, negative limit is not checked
+func CheckFlakyThreshold(flaky []FlakySpec, limit int) error {
+	if limit < 0 {
+		return nil
+	}
+
+	if len(flaky) > limit {
+		output.PrintLog(fmt.Sprintf("%s %d flaky specs exceed the threshold of %d", ui.IconCross, len(flaky), limit))
+		return fmt.Errorf("%d flaky specs exceed the threshold of %d", len(flaky), limit)
+	}
+
+	return nil
+}
@@ -0,0 +1,48 @@
+package runner
+
+import (
+	"testing"
+
+	"github.com/kubeshop/testkube/pkg/api/v1/testkube"
+	"github.com/onsi/ginkgo/v2/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFlaky(t *testing.T) {
+	flakySpec := newSpecReport([]string{"API"}, "eventually responds", types.SpecStatePassed)
+	flakySpec.NumAttempts = 3
+	failedSpec := newSpecReport([]string{"API"}, "always fails", types.SpecStateFailed)
+	failedSpec.NumAttempts = 3
+	reports := []types.Report{{
+		SuiteDescription: "API Suite",
+		SpecReports: types.SpecReports{
+			flakySpec,
+			failedSpec,
+			newSpecReport([]string{"API"}, "responds", types.SpecStatePassed),
+		},
+	}}
+
+	t.Run("FindFlakySpecs should only return specs passed after retries", func(t *testing.T) {
+		flaky := FindFlakySpecs(reports)
+		assert.Equal(t, []FlakySpec{{Step: "API Suite - [It] API eventually responds", Attempts: 3}}, flaky)
+		assert.Contains(t, FlakySpecsSummary(flaky), "API Suite - [It] API eventually responds (passed after 3 attempts)")
+	})
+
+	t.Run("MarkFlakySpecs should mark steps of flaky specs", func(t *testing.T) {
+		result := testkube.ExecutionResult{Steps: []testkube.ExecutionStepResult{
+			{Name: "API Suite - [It] API eventually responds", Status: "passed"},
+			{Name: "API Suite - [It] API responds", Status: "passed"},
+		}}
+		MarkFlakySpecs(&result, FindFlakySpecs(reports))
+
+		assert.Equal(t, []testkube.AssertionResult{{Name: "flaky", Status: "passed", ErrorMessage: "passed after 3 attempts"}}, result.Steps[0].AssertionResults)
+		assert.Empty(t, result.Steps[1].AssertionResults)
+	})
+
+	t.Run("CheckFlakyThreshold should fail when flaky specs exceed the threshold", func(t *testing.T) {
+		flaky := FindFlakySpecs(reports)
+		assert.NoError(t, CheckFlakyThreshold(flaky, -1))
+		assert.NoError(t, CheckFlakyThreshold(flaky, 1))
+		assert.ErrorContains(t, CheckFlakyThreshold(flaky, 0), "1 flaky specs exceed the threshold of 0")
+	})
+}
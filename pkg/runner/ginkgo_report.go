@@ -0,0 +1,45 @@
+package runner
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/onsi/ginkgo/v2/types"
+)
+
+// LoadGinkgoReports reads a ginkgo JSON report, it holds one report per suite
+func LoadGinkgoReports(path string) ([]types.Report, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	reports := []types.Report{}
+	if err = json.Unmarshal(data, &reports); err != nil {
+		return nil, fmt.Errorf("could not decode ginkgo JSON report %s: %w", path, err)
+	}
+
+	return reports, nil
+}
+
+// JunitTestName returns the JUnit test case name ginkgo generates for the spec
+func JunitTestName(spec types.SpecReport) string {
+	name := fmt.Sprintf("[%s]", spec.LeafNodeType)
+	if spec.FullText() != "" {
+		name = name + " " + spec.FullText()
+	}
+
+	labels := spec.Labels()
+	if len(labels) > 0 {
+		name = name + " [" + strings.Join(labels, ", ") + "]"
+	}
+
+	return strings.TrimSpace(name)
+}
+
+// SpecStepName returns the name of the execution step mapped from the spec
+func SpecStepName(report types.Report, spec types.SpecReport) string {
+	return StepName(report.SuiteDescription, JunitTestName(spec))
+}
@@ -0,0 +1,52 @@
+package runner
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/onsi/ginkgo/v2/reporters"
+	"github.com/onsi/ginkgo/v2/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func newSpecReport(texts []string, leaf string, state types.SpecState, labels ...string) types.SpecReport {
+	return types.SpecReport{
+		ContainerHierarchyTexts:  texts,
+		ContainerHierarchyLabels: [][]string{labels},
+		LeafNodeType:             types.NodeTypeIt,
+		LeafNodeText:             leaf,
+		LeafNodeLocation:         types.CodeLocation{FileName: "/data/repo/e2e/url_test.go", LineNumber: 14},
+		State:                    state,
+		NumAttempts:              1,
+		StartTime:                time.Date(2023, 4, 1, 10, 0, 0, 0, time.UTC),
+		EndTime:                  time.Date(2023, 4, 1, 10, 0, 2, 0, time.UTC),
+		RunTime:                  2 * time.Second,
+	}
+}
+
+func TestGinkgoReport(t *testing.T) {
+	t.Run("JunitTestName should match names generated by ginkgo JUnit reporter", func(t *testing.T) {
+		spec := newSpecReport([]string{"Try Google for a 200"}, "should return 200", types.SpecStatePassed, "smoke", "network")
+		assert.Equal(t, "[It] Try Google for a 200 should return 200 [smoke, network]", JunitTestName(spec))
+
+		suite := types.SpecReport{LeafNodeType: types.NodeTypeBeforeSuite}
+		assert.Equal(t, "[BeforeSuite]", JunitTestName(suite))
+	})
+
+	t.Run("LoadGinkgoReports should read reports written by ginkgo", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "report.json")
+		report := types.Report{
+			SuiteDescription: "E2E Integration Testing Suite",
+			SpecReports: types.SpecReports{
+				newSpecReport([]string{"Try Google for a 200"}, "should return 200", types.SpecStatePassed),
+			},
+		}
+		assert.NoError(t, reporters.GenerateJSONReport(report, path))
+
+		reports, err := LoadGinkgoReports(path)
+		assert.NoError(t, err)
+		assert.Len(t, reports, 1)
+		assert.Equal(t, "E2E Integration Testing Suite - [It] Try Google for a 200 should return 200", SpecStepName(reports[0], reports[0].SpecReports[0]))
+	})
+}
@@ -0,0 +1,235 @@
+package runner
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/kubeshop/testkube/pkg/executor/output"
+	"github.com/kubeshop/testkube/pkg/process"
+	"github.com/kubeshop/testkube/pkg/ui"
+)
+
+const (
+	ginkgoV1Module      = "github.com/onsi/ginkgo"
+	ginkgoModule        = "github.com/onsi/ginkgo/v2"
+	ginkgoCLIPackage    = ginkgoModule + "/ginkgo"
+	ginkgoVersionAuto   = "auto"
+	ginkgoVersionBundle = "bundled"
+)
+
+// FindGoMod returns the path of the go.mod closest to dir, looking up to the root directory
+func FindGoMod(dir, root string) (string, error) {
+	dir = filepath.Clean(dir)
+	root = filepath.Clean(root)
+	for {
+		goMod := filepath.Join(dir, "go.mod")
+		if _, err := os.Stat(goMod); err == nil {
+			return goMod, nil
+		}
+
+		parent := filepath.Dir(dir)
+		if dir == root || parent == dir {
+			return "", fmt.Errorf("could not find go.mod in %s or its parents", dir)
+		}
+		dir = parent
+	}
+}
+
+// ParseGoModRequirements returns module versions required in go.mod data, replace directives are ignored
+func ParseGoModRequirements(data []byte) map[string]string {
+	requirements := make(map[string]string)
+	inBlock := false
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.Index(line, "//"); i != -1 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+
+		switch {
+		case len(fields) == 0:
+			continue
+		case inBlock && fields[0] == ")":
+			inBlock = false
+		case fields[0] == "require" && len(fields) == 2 && fields[1] == "(":
+			inBlock = true
+		case fields[0] == "require" && len(fields) >= 3:
+			requirements[fields[1]] = fields[2]
+		case inBlock && len(fields) >= 2:
+			requirements[fields[0]] = fields[1]
+		}
+	}
+
+	return requirements
+}
+
+// GinkgoModuleVersion returns the Ginkgo v2 version required by the module containing runPath
+func GinkgoModuleVersion(runPath, root string) (string, error) {
+	goMod, err := FindGoMod(runPath, root)
+	if err != nil {
+		return "", err
+	}
+
+	data, err := os.ReadFile(goMod)
+	if err != nil {
+		return "", err
+	}
+
+	version, found := ParseGoModRequirements(data)[ginkgoModule]
+	if !found {
+		return "", fmt.Errorf("%s does not require %s", goMod, ginkgoModule)
+	}
+
+	return version, nil
+}
+
+// GinkgoCLIVersion returns the version reported by the ginkgo binary, e.g. v2.9.2
+func GinkgoCLIVersion(bin string) (string, error) {
+	out, err := process.Execute(bin, "version")
+	if err != nil {
+		return "", err
+	}
+
+	fields := strings.Fields(string(out))
+	if len(fields) == 0 {
+		return "", fmt.Errorf("unexpected ginkgo version output: %s", out)
+	}
+
+	return "v" + strings.TrimPrefix(fields[len(fields)-1], "v"), nil
+}
+
+// ResolveGinkgoBin returns the ginkgo binary matching the requested version. With "auto" the version required
+// in the suite's go.mod is used. Preinstalled "ginkgo-<version>" binaries are preferred, otherwise the CLI
+// is installed into the data directory. The bundled binary is used whenever a matching one can't be provided.
+func ResolveGinkgoBin(requested, dataDir, runPath, root string) string {
+	if requested == "" || requested == ginkgoVersionBundle {
+		return ginkgoBin
+	}
+
+	version := requested
+	if requested == ginkgoVersionAuto {
+		moduleVersion, err := GinkgoModuleVersion(runPath, root)
+		if err != nil {
+			output.PrintLog(fmt.Sprintf("%s Using bundled ginkgo, could not read Ginkgo version from go.mod: %s", ui.IconWarning, err.Error()))
+			return ginkgoBin
+		}
+		version = moduleVersion
+	}
+	version = "v" + strings.TrimPrefix(version, "v")
+
+	bundledVersion, err := GinkgoCLIVersion(ginkgoBin)
+	if err == nil && bundledVersion == version {
+		return ginkgoBin
+	}
+
+	if bin, err := exec.LookPath(ginkgoBin + "-" + version); err == nil {
+		output.PrintLog(fmt.Sprintf("%s Using preinstalled ginkgo %s", ui.IconCheckMark, version))
+		return bin
+	}
+
+	output.PrintLog(fmt.Sprintf("%s Installing ginkgo %s to match the suite (bundled: %s)", ui.IconWorld, version, bundledVersion))
+	binDir := filepath.Join(dataDir, "ginkgo", version)
+	bin := filepath.Join(binDir, ginkgoBin)
+	if _, err := os.Stat(bin); err == nil {
+		return bin
+	}
+
+	cmd := exec.Command("go", "install", ginkgoCLIPackage+"@"+version)
+	cmd.Env = append(os.Environ(), "GOBIN="+binDir)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		output.PrintLog(fmt.Sprintf("%s Using bundled ginkgo, could not install ginkgo %s: %s\n%s", ui.IconWarning, version, err.Error(), out))
+		return ginkgoBin
+	}
+
+	output.PrintLog(fmt.Sprintf("%s Installed ginkgo %s", ui.IconCheckMark, version))
+	return bin
+}
+
+// FindGinkgoV1Imports returns test files below dir importing Ginkgo v1 packages, vendor and testdata are skipped
+func FindGinkgoV1Imports(dir string) ([]string, error) {
+	files := []string{}
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			if path != dir && (d.Name() == "vendor" || d.Name() == "testdata" || strings.HasPrefix(d.Name(), ".")) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if !strings.HasSuffix(path, "_test.go") {
+			return nil
+		}
+
+		file, err := parser.ParseFile(token.NewFileSet(), path, nil, parser.ImportsOnly)
+		if err != nil {
+			// broken files are reported by the go toolchain itself
+			return nil
+		}
+
+		for _, spec := range file.Imports {
+			importPath, err := strconv.Unquote(spec.Path.Value)
+			if err != nil {
+				continue
+			}
+
+			if isGinkgoV1Import(importPath) {
+				files = append(files, path)
+				break
+			}
+		}
+
+		return nil
+	})
+
+	return files, err
+}
+
+// CheckGinkgoV1 fails with a migration hint when the suite in dir still uses Ginkgo v1
+func CheckGinkgoV1(dir string) error {
+	output.PrintLog(fmt.Sprintf("%s Checking suite for Ginkgo v1 usage", ui.IconWorld))
+
+	files, err := FindGinkgoV1Imports(dir)
+	if err != nil {
+		output.PrintLog(fmt.Sprintf("%s Could not check suite for Ginkgo v1 usage: %s", ui.IconWarning, err.Error()))
+		return nil
+	}
+
+	if len(files) == 0 {
+		output.PrintLog(fmt.Sprintf("%s No Ginkgo v1 usage found", ui.IconCheckMark))
+		return nil
+	}
+
+	for i := range files {
+		if rel, err := filepath.Rel(dir, files[i]); err == nil {
+			files[i] = rel
+		}
+	}
+
+	output.PrintLog(fmt.Sprintf("%s Ginkgo v1 suites are not supported, found v1 imports in: %s", ui.IconCross, strings.Join(files, ", ")))
+	return fmt.Errorf("ginkgo v1 suites are not supported, found %s imports in: %s; "+
+		"migrate the suite to %s, see https://onsi.github.io/ginkgo/MIGRATING_TO_V2",
+		ginkgoV1Module, strings.Join(files, ", "), ginkgoModule)
+}
+
+func isGinkgoV1Import(importPath string) bool {
+	if importPath == ginkgoV1Module {
+		return true
+	}
+
+	return strings.HasPrefix(importPath, ginkgoV1Module+"/") &&
+		importPath != ginkgoModule && !strings.HasPrefix(importPath, ginkgoModule+"/")
+}
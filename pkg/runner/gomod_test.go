@@ -0,0 +1,81 @@
+package runner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const goModWithGinkgo = `module example.com/suite
+
+go 1.18
+
+require github.com/stretchr/testify v1.8.1 // indirect
+
+require (
+	github.com/onsi/ginkgo/v2 v2.9.2
+	github.com/onsi/gomega v1.27.4 // indirect
+)
+
+replace github.com/onsi/gomega => ../gomega
+`
+
+func TestGoMod(t *testing.T) {
+	t.Run("ParseGoModRequirements should read single line and block requirements", func(t *testing.T) {
+		requirements := ParseGoModRequirements([]byte(goModWithGinkgo))
+		assert.Equal(t, "v1.8.1", requirements["github.com/stretchr/testify"])
+		assert.Equal(t, "v2.9.2", requirements["github.com/onsi/ginkgo/v2"])
+		assert.Equal(t, "v1.27.4", requirements["github.com/onsi/gomega"])
+	})
+
+	t.Run("GinkgoModuleVersion should find go.mod in parent directories", func(t *testing.T) {
+		root := t.TempDir()
+		suite := filepath.Join(root, "tests", "e2e")
+		assert.NoError(t, os.MkdirAll(suite, os.ModePerm))
+		assert.NoError(t, os.WriteFile(filepath.Join(root, "go.mod"), []byte(goModWithGinkgo), 0644))
+
+		version, err := GinkgoModuleVersion(suite, root)
+		assert.NoError(t, err)
+		assert.Equal(t, "v2.9.2", version)
+	})
+
+	t.Run("GinkgoModuleVersion should not look above the root directory", func(t *testing.T) {
+		parent := t.TempDir()
+		root := filepath.Join(parent, "repo")
+		assert.NoError(t, os.MkdirAll(root, os.ModePerm))
+		assert.NoError(t, os.WriteFile(filepath.Join(parent, "go.mod"), []byte(goModWithGinkgo), 0644))
+
+		_, err := GinkgoModuleVersion(root, root)
+		assert.Error(t, err)
+	})
+
+	t.Run("ResolveGinkgoBin should use the bundled binary when requested", func(t *testing.T) {
+		assert.Equal(t, ginkgoBin, ResolveGinkgoBin("bundled", "", "", ""))
+		assert.Equal(t, ginkgoBin, ResolveGinkgoBin("", "", "", ""))
+	})
+
+	t.Run("CheckGinkgoV1 should reject suites importing Ginkgo v1", func(t *testing.T) {
+		dir := t.TempDir()
+		assert.NoError(t, os.MkdirAll(filepath.Join(dir, "legacy"), os.ModePerm))
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, "legacy", "legacy_test.go"), []byte(`package legacy
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+`), 0644))
+
+		err := CheckGinkgoV1(dir)
+		assert.ErrorContains(t, err, "legacy/legacy_test.go")
+		assert.ErrorContains(t, err, "MIGRATING_TO_V2")
+	})
+
+	t.Run("CheckGinkgoV1 should accept Ginkgo v2 suites", func(t *testing.T) {
+		err := CheckGinkgoV1(filepath.Join("..", "..", "examples"))
+		assert.NoError(t, err)
+		assert.False(t, isGinkgoV1Import("github.com/onsi/ginkgo/v2/dsl/core"))
+		assert.True(t, isGinkgoV1Import("github.com/onsi/ginkgo/extensions/table"))
+	})
+}
@@ -0,0 +1,238 @@
+package runner
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/kubeshop/testkube/pkg/api/v1/testkube"
+	"github.com/kubeshop/testkube/pkg/envs"
+	"github.com/kubeshop/testkube/pkg/executor/output"
+	"github.com/kubeshop/testkube/pkg/ui"
+)
+
+const (
+	// GoPrivateUsernameVar is the execution variable with the username used to download private Go modules
+	GoPrivateUsernameVar = "GoPrivateUsername"
+	// GoPrivateTokenVar is the execution variable with the token used to download private Go modules
+	GoPrivateTokenVar = "GoPrivateToken"
+
+	// defaultGoPrivateUsername is used when only a token is provided, most git hosts accept any username then
+	defaultGoPrivateUsername = "git"
+)
+
+// GoPrivateConfig is the environment set up for downloading private Go modules during a single execution
+type GoPrivateConfig struct {
+	dir      string
+	previous map[string]*string
+}
+
+// GoPrivateCredentials returns the credentials for private Go modules, dedicated variables take precedence
+// over the git credentials used for checking out the repository
+func GoPrivateCredentials(execution testkube.Execution, params envs.Params) (username, token string) {
+	username = execution.Variables[GoPrivateUsernameVar].Value
+	token = execution.Variables[GoPrivateTokenVar].Value
+	if username != "" || token != "" {
+		return username, token
+	}
+
+	if params.GitUsername != "" || params.GitToken != "" {
+		return params.GitUsername, params.GitToken
+	}
+
+	if execution.Content != nil && execution.Content.Repository != nil {
+		return execution.Content.Repository.Username, execution.Content.Repository.Token
+	}
+
+	return "", ""
+}
+
+// HideGoPrivateVariables keeps the dedicated credentials away from the specs: they are removed from a copy of
+// the execution variables, so the env manager holding the original map still obfuscates them in the output,
+// and from the executor environment the specs inherit
+func HideGoPrivateVariables(execution *testkube.Execution) {
+	variables := make(map[string]testkube.Variable, len(execution.Variables))
+	for name, variable := range execution.Variables {
+		if name != GoPrivateUsernameVar && name != GoPrivateTokenVar {
+			variables[name] = variable
+		}
+	}
+	execution.Variables = variables
+
+	os.Unsetenv(GoPrivateUsernameVar)
+	os.Unsetenv(GoPrivateTokenVar)
+}
+
+// GoPrivatePatterns returns the module path patterns treated as private, taken from the comma separated
+// GinkgoGoPrivate param or derived from the organisation of the tested repository
+func GoPrivatePatterns(param, repoURI string) []string {
+	patterns := []string{}
+	for _, pattern := range strings.Split(param, ",") {
+		pattern = strings.TrimSpace(pattern)
+		if pattern != "" {
+			patterns = append(patterns, pattern)
+		}
+	}
+
+	if len(patterns) > 0 {
+		return patterns
+	}
+
+	host, path := splitRepositoryURI(repoURI)
+	if host == "" {
+		return patterns
+	}
+
+	org := strings.Split(strings.Trim(path, "/"), "/")[0]
+	if org == "" {
+		return []string{host}
+	}
+
+	return []string{host + "/" + org}
+}
+
+// PrepareGoPrivate sets GOPRIVATE and GONOSUMDB for the given patterns and makes the credentials available
+// to the go command through a netrc file and to git through a credential store file, the credentials are never
+// put into the environment, call Cleanup to restore the environment
+func PrepareGoPrivate(dataDir string, patterns []string, username, token string) (*GoPrivateConfig, error) {
+	output.PrintLog(fmt.Sprintf("%s Configuring access to private Go modules: %s", ui.IconWorld, strings.Join(patterns, ",")))
+
+	if username == "" {
+		username = defaultGoPrivateUsername
+	}
+
+	dir, err := os.MkdirTemp(dataDir, "goprivate")
+	if err != nil {
+		return nil, fmt.Errorf("could not create private Go modules config directory: %w", err)
+	}
+
+	config := &GoPrivateConfig{
+		dir:      dir,
+		previous: make(map[string]*string),
+	}
+
+	hosts := goPrivateHosts(patterns)
+	netrc := strings.Builder{}
+	for _, host := range hosts {
+		netrc.WriteString(fmt.Sprintf("machine %s login %s password %s\n", host, username, token))
+	}
+
+	netrcPath := filepath.Join(dir, ".netrc")
+	if err = os.WriteFile(netrcPath, []byte(netrc.String()), 0600); err != nil {
+		config.Cleanup()
+		return nil, fmt.Errorf("could not write netrc file: %w", err)
+	}
+
+	config.setenv("GOPRIVATE", appendEnvList(os.Getenv("GOPRIVATE"), patterns))
+	config.setenv("GONOSUMDB", appendEnvList(os.Getenv("GONOSUMDB"), patterns))
+	config.setenv("NETRC", netrcPath)
+
+	credentials := url.UserPassword(username, token).String()
+	store := strings.Builder{}
+	for _, host := range hosts {
+		store.WriteString(fmt.Sprintf("https://%s@%s\n", credentials, host))
+	}
+
+	storePath := filepath.Join(dir, ".git-credentials")
+	if err = os.WriteFile(storePath, []byte(store.String()), 0600); err != nil {
+		config.Cleanup()
+		return nil, fmt.Errorf("could not write git credentials file: %w", err)
+	}
+
+	// git reads the credential helper and url rewrites from GIT_CONFIG_KEY_n/GIT_CONFIG_VALUE_n without
+	// touching the global config, ssh URLs are rewritten to https so they use the stored credentials too
+	count, _ := strconv.Atoi(os.Getenv("GIT_CONFIG_COUNT"))
+	config.setGitConfig(&count, "credential.helper", fmt.Sprintf("store --file='%s'", storePath))
+	for _, host := range hosts {
+		for _, prefix := range []string{"git@" + host + ":", "ssh://git@" + host + "/"} {
+			config.setGitConfig(&count, fmt.Sprintf("url.https://%s/.insteadOf", host), prefix)
+		}
+	}
+	config.setenv("GIT_CONFIG_COUNT", strconv.Itoa(count))
+
+	output.PrintLog(fmt.Sprintf("%s Private Go modules access configured for hosts: %s", ui.IconCheckMark, strings.Join(hosts, ",")))
+	return config, nil
+}
+
+// Cleanup restores the environment and removes the credentials written for the execution
+func (c *GoPrivateConfig) Cleanup() error {
+	for name, value := range c.previous {
+		if value == nil {
+			os.Unsetenv(name)
+		} else {
+			os.Setenv(name, *value)
+		}
+	}
+
+	return os.RemoveAll(c.dir)
+}
+
+func (c *GoPrivateConfig) setenv(name, value string) {
+	if _, found := c.previous[name]; !found {
+		if previous, ok := os.LookupEnv(name); ok {
+			c.previous[name] = &previous
+		} else {
+			c.previous[name] = nil
+		}
+	}
+
+	os.Setenv(name, value)
+}
+
+// setGitConfig adds a git config entry as the next GIT_CONFIG_KEY_n/GIT_CONFIG_VALUE_n pair
+func (c *GoPrivateConfig) setGitConfig(count *int, key, value string) {
+	c.setenv(fmt.Sprintf("GIT_CONFIG_KEY_%d", *count), key)
+	c.setenv(fmt.Sprintf("GIT_CONFIG_VALUE_%d", *count), value)
+	*count++
+}
+
+// goPrivateHosts returns the distinct hosts of the patterns, wildcard hosts can't be used for rewrites
+func goPrivateHosts(patterns []string) []string {
+	hosts := []string{}
+	seen := make(map[string]bool)
+	for _, pattern := range patterns {
+		host := strings.Split(pattern, "/")[0]
+		if host == "" || strings.ContainsAny(host, "*?[") || seen[host] {
+			continue
+		}
+
+		seen[host] = true
+		hosts = append(hosts, host)
+	}
+
+	return hosts
+}
+
+// splitRepositoryURI returns host and path of https and scp-like ssh git URIs
+func splitRepositoryURI(uri string) (host, path string) {
+	if strings.Contains(uri, "://") {
+		u, err := url.Parse(uri)
+		if err != nil {
+			return "", ""
+		}
+
+		return u.Hostname(), strings.TrimSuffix(u.Path, ".git")
+	}
+
+	if at := strings.Index(uri, "@"); at != -1 {
+		uri = uri[at+1:]
+	}
+
+	parts := strings.SplitN(uri, ":", 2)
+	if len(parts) != 2 {
+		return "", ""
+	}
+
+	return parts[0], strings.TrimSuffix(parts[1], ".git")
+}
+
+func appendEnvList(current string, values []string) string {
+	if current == "" {
+		return strings.Join(values, ",")
+	}
+
+	return current + "," + strings.Join(values, ",")
+}
@@ -0,0 +1,167 @@
+package runner
+
+import (
+	"html/template"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/onsi/ginkgo/v2/types"
+)
+
+// htmlSuite is a suite as rendered in the HTML report
+type htmlSuite struct {
+	Description string
+	Path        string
+	RunTime     time.Duration
+	Passed      int
+	Failed      int
+	Skipped     int
+	Flaky       int
+	Specs       []htmlSpec
+}
+
+// htmlSpec is a spec or suite level node as rendered in the HTML report
+type htmlSpec struct {
+	State      string
+	Failed     bool
+	NodeType   string
+	Containers []string
+	Text       string
+	Labels     []string
+	RunTime    time.Duration
+	Attempts   int
+	Failure    string
+	Location   string
+}
+
+// htmlReport is the data of the HTML report
+type htmlReport struct {
+	Suites  []htmlSuite
+	Passed  int
+	Failed  int
+	Skipped int
+	Flaky   int
+	RunTime time.Duration
+}
+
+var htmlReportTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Ginkgo test report</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2em; color: #24292f; }
+h1, h2 { font-weight: 600; }
+.totals span, .state { display: inline-block; padding: 0.1em 0.6em; border-radius: 1em; font-size: 0.9em; margin-right: 0.4em; }
+.passed { background: #dafbe1; color: #116329; }
+.failed { background: #ffebe9; color: #a40e26; }
+.skipped { background: #eaeef2; color: #57606a; }
+.flaky { background: #fff8c5; color: #7d4e00; }
+table { border-collapse: collapse; width: 100%; margin-bottom: 2em; }
+th, td { text-align: left; padding: 0.4em 0.6em; border-bottom: 1px solid #d0d7de; vertical-align: top; }
+.containers { color: #57606a; }
+.label { font-size: 0.8em; background: #ddf4ff; color: #0550ae; padding: 0 0.4em; border-radius: 0.4em; margin-right: 0.2em; }
+pre { white-space: pre-wrap; background: #f6f8fa; padding: 0.6em; margin: 0.4em 0 0; }
+.location { color: #57606a; font-size: 0.9em; }
+</style>
+</head>
+<body>
+<h1>Ginkgo test report</h1>
+<p class="totals">
+<span class="passed">{{.Passed}} passed</span><span class="failed">{{.Failed}} failed</span><span class="skipped">{{.Skipped}} skipped</span><span class="flaky">{{.Flaky}} flaky</span>
+in {{.RunTime}}
+</p>
+{{range .Suites}}
+<h2>{{.Description}}</h2>
+<p class="location">{{.Path}} &middot; {{.Passed}} passed, {{.Failed}} failed, {{.Skipped}} skipped, {{.Flaky}} flaky in {{.RunTime}}</p>
+<table>
+<thead><tr><th>State</th><th>Spec</th><th>Duration</th></tr></thead>
+<tbody>
+{{range .Specs}}
+<tr>
+<td><span class="state {{if .Failed}}failed{{else if eq .State "passed"}}{{if gt .Attempts 1}}flaky{{else}}passed{{end}}{{else}}skipped{{end}}">{{.State}}</span></td>
+<td>
+{{if .NodeType}}<strong>[{{.NodeType}}]</strong> {{end}}{{range .Containers}}<span class="containers">{{.}} &rsaquo;</span> {{end}}{{.Text}}
+{{range .Labels}}<span class="label">{{.}}</span>{{end}}
+{{if gt .Attempts 1}}<div class="location">passed after {{.Attempts}} attempts</div>{{end}}
+{{if .Failure}}<details open><summary class="location">{{.Location}}</summary><pre>{{.Failure}}</pre></details>{{end}}
+</td>
+<td>{{.RunTime}}</td>
+</tr>
+{{end}}
+</tbody>
+</table>
+{{end}}
+</body>
+</html>
+`))
+
+// RenderHTMLReport renders a self-contained HTML report of the suites, their specs with labels and durations,
+// failures with messages and locations and flaky specs
+func RenderHTMLReport(reports []types.Report, w io.Writer) error {
+	data := htmlReport{}
+	for _, report := range reports {
+		suite := htmlSuite{Description: report.SuiteDescription, Path: report.SuitePath, RunTime: report.RunTime}
+		for _, spec := range report.SpecReports {
+			if spec.LeafNodeType != types.NodeTypeIt && !spec.Failed() {
+				continue
+			}
+
+			htmlSpec := htmlSpec{
+				State:      spec.State.String(),
+				Failed:     spec.Failed(),
+				Containers: spec.ContainerHierarchyTexts,
+				Text:       spec.LeafNodeText,
+				Labels:     spec.Labels(),
+				RunTime:    spec.RunTime.Round(time.Millisecond),
+				Attempts:   spec.NumAttempts,
+			}
+			if spec.LeafNodeType != types.NodeTypeIt {
+				htmlSpec.NodeType = spec.LeafNodeType.String()
+			}
+			if spec.Failed() {
+				htmlSpec.Failure = strings.TrimSpace(spec.Failure.Message)
+				htmlSpec.Location = spec.Failure.Location.String()
+			}
+
+			switch {
+			case spec.Failed():
+				suite.Failed++
+			case spec.State == types.SpecStatePassed:
+				suite.Passed++
+				if spec.NumAttempts > 1 {
+					suite.Flaky++
+				}
+			default:
+				suite.Skipped++
+			}
+			suite.Specs = append(suite.Specs, htmlSpec)
+		}
+
+		data.Passed += suite.Passed
+		data.Failed += suite.Failed
+		data.Skipped += suite.Skipped
+		data.Flaky += suite.Flaky
+		data.RunTime += suite.RunTime
+		data.Suites = append(data.Suites, suite)
+	}
+
+	return htmlReportTemplate.Execute(w, data)
+}
+
+// WriteHTMLReport writes the HTML report to path
+func WriteHTMLReport(reports []types.Report, path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	if err = RenderHTMLReport(reports, file); err != nil {
+		file.Close()
+		return err
+	}
+
+	return file.Close()
+}
@@ -0,0 +1,56 @@
+package runner
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/onsi/ginkgo/v2/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHTMLReport(t *testing.T) {
+	failed := newSpecReport([]string{"API", "errors"}, "returns <500>", types.SpecStateFailed, "network")
+	failed.Failure = types.Failure{
+		Message:  "Expected 200 to equal 500",
+		Location: types.CodeLocation{FileName: "/data/repo/e2e/url_test.go", LineNumber: 21},
+	}
+	flaky := newSpecReport([]string{"API"}, "responds", types.SpecStatePassed, "smoke")
+	flaky.NumAttempts = 3
+
+	reports := []types.Report{{
+		SuiteDescription: "E2E Suite",
+		SuitePath:        "/data/repo/e2e",
+		SpecReports: types.SpecReports{
+			{LeafNodeType: types.NodeTypeBeforeSuite, State: types.SpecStatePassed},
+			flaky,
+			failed,
+			newSpecReport([]string{"API"}, "is slow", types.SpecStateSkipped),
+		},
+	}}
+
+	t.Run("RenderHTMLReport should render suites, failures, labels and flaky specs", func(t *testing.T) {
+		b := strings.Builder{}
+		assert.NoError(t, RenderHTMLReport(reports, &b))
+		html := b.String()
+
+		assert.Contains(t, html, `<span class="passed">1 passed</span><span class="failed">1 failed</span><span class="skipped">1 skipped</span><span class="flaky">1 flaky</span>`)
+		assert.Contains(t, html, "<h2>E2E Suite</h2>")
+		assert.Contains(t, html, "returns &lt;500&gt;")
+		assert.Contains(t, html, `<span class="label">network</span>`)
+		assert.Contains(t, html, "<pre>Expected 200 to equal 500</pre>")
+		assert.Contains(t, html, "/data/repo/e2e/url_test.go:21")
+		assert.Contains(t, html, "passed after 3 attempts")
+		assert.NotContains(t, html, "[BeforeSuite]")
+	})
+
+	t.Run("WriteHTMLReport should write the report to a file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "report.html")
+		assert.NoError(t, WriteHTMLReport(reports, path))
+
+		data, err := os.ReadFile(path)
+		assert.NoError(t, err)
+		assert.True(t, strings.HasPrefix(string(data), "<!DOCTYPE html>"))
+	})
+}
@@ -0,0 +1,202 @@
+package runner
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/onsi/ginkgo/v2/reporters"
+	"github.com/onsi/ginkgo/v2/types"
+)
+
+// junitLabelProperty is the name of the test case property holding a spec label
+const junitLabelProperty = "label"
+
+// NormalizedJunitTestSuites is a JUnit report with test case properties, which ginkgo doesn't write
+type NormalizedJunitTestSuites struct {
+	XMLName    xml.Name                   `xml:"testsuites"`
+	Tests      int                        `xml:"tests,attr"`
+	Disabled   int                        `xml:"disabled,attr"`
+	Errors     int                        `xml:"errors,attr"`
+	Failures   int                        `xml:"failures,attr"`
+	Time       float64                    `xml:"time,attr"`
+	TestSuites []NormalizedJunitTestSuite `xml:"testsuite"`
+}
+
+// NormalizedJunitTestSuite is a JUnit test suite of a single package
+type NormalizedJunitTestSuite struct {
+	Name       string                    `xml:"name,attr"`
+	Package    string                    `xml:"package,attr"`
+	Tests      int                       `xml:"tests,attr"`
+	Disabled   int                       `xml:"disabled,attr"`
+	Skipped    int                       `xml:"skipped,attr"`
+	Errors     int                       `xml:"errors,attr"`
+	Failures   int                       `xml:"failures,attr"`
+	Time       float64                   `xml:"time,attr"`
+	Timestamp  string                    `xml:"timestamp,attr"`
+	Properties reporters.JUnitProperties `xml:"properties"`
+	TestCases  []NormalizedJunitTestCase `xml:"testcase"`
+}
+
+// NormalizedJunitTestCase is a JUnit test case named after the It text and classified by package and containers
+type NormalizedJunitTestCase struct {
+	Name       string                     `xml:"name,attr"`
+	Classname  string                     `xml:"classname,attr"`
+	Status     string                     `xml:"status,attr"`
+	Time       float64                    `xml:"time,attr"`
+	Properties *reporters.JUnitProperties `xml:"properties,omitempty"`
+	Skipped    *reporters.JUnitSkipped    `xml:"skipped,omitempty"`
+	Error      *reporters.JUnitError      `xml:"error,omitempty"`
+	Failure    *reporters.JUnitFailure    `xml:"failure,omitempty"`
+	SystemOut  string                     `xml:"system-out,omitempty"`
+	SystemErr  string                     `xml:"system-err,omitempty"`
+}
+
+// NormalizeJunitReport rewrites test cases of a ginkgo JUnit report the way common CI parsers expect them,
+// classname is the package path relative to runPath followed by the containers, name is the It text and
+// labels become properties. Specs are looked up in the JSON reports, test cases missing there, e.g. from
+// a previous report combined with a rerun, are normalized from their JUnit names.
+func NormalizeJunitReport(report reporters.JUnitTestSuites, reports []types.Report, runPath string) NormalizedJunitTestSuites {
+	specs := make(map[string][]types.SpecReport)
+	for _, r := range reports {
+		for _, spec := range r.SpecReports {
+			key := junitCaseKey(r.SuitePath, JunitTestName(spec))
+			specs[key] = append(specs[key], spec)
+		}
+	}
+
+	normalized := NormalizedJunitTestSuites{
+		Tests:    report.Tests,
+		Disabled: report.Disabled,
+		Errors:   report.Errors,
+		Failures: report.Failures,
+		Time:     report.Time,
+	}
+	for _, suite := range report.TestSuites {
+		pkg := junitPackageClassname(suite.Package, runPath)
+		normalizedSuite := NormalizedJunitTestSuite{
+			Name:       suite.Name,
+			Package:    suite.Package,
+			Tests:      suite.Tests,
+			Disabled:   suite.Disabled,
+			Skipped:    suite.Skipped,
+			Errors:     suite.Errors,
+			Failures:   suite.Failures,
+			Time:       suite.Time,
+			Timestamp:  suite.Timestamp,
+			Properties: suite.Properties,
+		}
+
+		for _, testCase := range suite.TestCases {
+			normalizedCase := NormalizedJunitTestCase{
+				Status:    testCase.Status,
+				Time:      testCase.Time,
+				Skipped:   testCase.Skipped,
+				Error:     testCase.Error,
+				Failure:   testCase.Failure,
+				SystemOut: testCase.SystemOut,
+				SystemErr: testCase.SystemErr,
+			}
+
+			// the same name can be reported more than once, e.g. by table entries, so specs are used up in order
+			var labels []string
+			key := junitCaseKey(suite.Package, testCase.Name)
+			if found := specs[key]; len(found) > 0 {
+				spec := found[0]
+				specs[key] = found[1:]
+				normalizedCase.Classname = strings.Join(append([]string{pkg}, spec.ContainerHierarchyTexts...), ".")
+				normalizedCase.Name = junitSpecName(spec)
+				labels = spec.Labels()
+			} else {
+				normalizedCase.Classname = pkg
+				normalizedCase.Name, labels = parseJunitTestName(testCase.Name)
+			}
+
+			if len(labels) > 0 {
+				normalizedCase.Properties = &reporters.JUnitProperties{}
+				for _, label := range labels {
+					normalizedCase.Properties.Properties = append(normalizedCase.Properties.Properties,
+						reporters.JUnitProperty{Name: junitLabelProperty, Value: label})
+				}
+			}
+			normalizedSuite.TestCases = append(normalizedSuite.TestCases, normalizedCase)
+		}
+
+		normalized.TestSuites = append(normalized.TestSuites, normalizedSuite)
+	}
+
+	return normalized
+}
+
+// NormalizeJunitReportFile normalizes the JUnit report at path in place, so it's scraped under the configured
+// name, and keeps the report written by ginkgo in originalDir, outside of the scraped reports so CI parsers don't
+// count specs twice. The path of the kept report is returned, steps are mapped from it so their names don't change.
+func NormalizeJunitReportFile(path, originalDir string, reports []types.Report, runPath string) (string, error) {
+	report, err := LoadJunitReport(path)
+	if err != nil {
+		return "", err
+	}
+
+	if err = os.MkdirAll(originalDir, os.ModePerm); err != nil {
+		return "", fmt.Errorf("could not keep JUnit report written by ginkgo: %w", err)
+	}
+
+	originalPath := filepath.Join(originalDir, filepath.Base(path))
+	if err = writeXMLReport(report, originalPath); err != nil {
+		return "", fmt.Errorf("could not keep JUnit report written by ginkgo: %w", err)
+	}
+
+	if err = writeXMLReport(NormalizeJunitReport(report, reports, runPath), path); err != nil {
+		return "", fmt.Errorf("could not write normalized JUnit report: %w", err)
+	}
+
+	return originalPath, nil
+}
+
+func junitCaseKey(suitePath, name string) string {
+	return suitePath + "\x00" + name
+}
+
+// junitPackageClassname returns the package path relative to runPath with dots as separators,
+// packages outside of runPath and runPath itself are named after their directory
+func junitPackageClassname(pkg, runPath string) string {
+	if rel, err := filepath.Rel(runPath, pkg); err == nil && rel != "." && !strings.HasPrefix(rel, "..") {
+		return strings.ReplaceAll(filepath.ToSlash(rel), "/", ".")
+	}
+
+	return filepath.Base(pkg)
+}
+
+// junitSpecName returns the It text of a spec, other nodes are named after their type, e.g. "BeforeSuite"
+func junitSpecName(spec types.SpecReport) string {
+	if spec.LeafNodeType == types.NodeTypeIt {
+		return spec.LeafNodeText
+	}
+
+	return strings.TrimSpace(fmt.Sprintf("%s %s", spec.LeafNodeType, spec.LeafNodeText))
+}
+
+// parseJunitTestName splits a ginkgo JUnit test name, e.g. "[It] API returns 500 [e2e, slow]", into
+// the spec text and labels, node types other than It are kept without brackets
+func parseJunitTestName(name string) (string, []string) {
+	var labels []string
+	if strings.HasSuffix(name, "]") {
+		if i := strings.LastIndex(name, " ["); i != -1 {
+			labels = strings.Split(strings.TrimSuffix(name[i+2:], "]"), ", ")
+			name = name[:i]
+		}
+	}
+
+	if strings.HasPrefix(name, "[It] ") {
+		return strings.TrimPrefix(name, "[It] "), labels
+	}
+	if strings.HasPrefix(name, "[") {
+		if i := strings.Index(name, "]"); i != -1 {
+			name = strings.TrimSpace(name[1:i] + " " + strings.TrimSpace(name[i+1:]))
+		}
+	}
+
+	return name, labels
+}
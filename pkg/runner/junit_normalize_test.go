@@ -0,0 +1,97 @@
+package runner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/joshdk/go-junit"
+	"github.com/onsi/ginkgo/v2/reporters"
+	"github.com/onsi/ginkgo/v2/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJunitNormalize(t *testing.T) {
+	report := types.Report{
+		SuitePath:        "/data/repo/e2e/api",
+		SuiteDescription: "API Suite",
+		SpecReports: types.SpecReports{
+			{LeafNodeType: types.NodeTypeBeforeSuite, State: types.SpecStatePassed},
+			newSpecReport([]string{"API", "errors"}, "returns 500", types.SpecStateFailed, "smoke", "network"),
+			newSpecReport([]string{"API"}, "responds", types.SpecStatePassed),
+		},
+	}
+
+	t.Run("NormalizeJunitReport should set classname, name and label properties", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "report.xml")
+		assert.NoError(t, reporters.GenerateJUnitReport(report, path))
+		junitReport, err := LoadJunitReport(path)
+		assert.NoError(t, err)
+
+		normalized := NormalizeJunitReport(junitReport, []types.Report{report}, "/data/repo")
+		assert.Len(t, normalized.TestSuites, 1)
+		cases := normalized.TestSuites[0].TestCases
+		assert.Len(t, cases, 3)
+
+		assert.Equal(t, "e2e.api", cases[0].Classname)
+		assert.Equal(t, "BeforeSuite", cases[0].Name)
+		assert.Nil(t, cases[0].Properties)
+
+		assert.Equal(t, "e2e.api.API.errors", cases[1].Classname)
+		assert.Equal(t, "returns 500", cases[1].Name)
+		assert.Equal(t, "failed", cases[1].Status)
+		assert.NotNil(t, cases[1].Failure)
+		assert.Equal(t, []reporters.JUnitProperty{{Name: "label", Value: "smoke"}, {Name: "label", Value: "network"}}, cases[1].Properties.Properties)
+
+		assert.Equal(t, "e2e.api.API", cases[2].Classname)
+		assert.Equal(t, "responds", cases[2].Name)
+	})
+
+	t.Run("NormalizeJunitReport should parse JUnit names of specs missing in the JSON report", func(t *testing.T) {
+		junitReport := reporters.JUnitTestSuites{TestSuites: []reporters.JUnitTestSuite{{
+			Name:    "API Suite",
+			Package: "/data/repo",
+			TestCases: []reporters.JUnitTestCase{
+				{Name: "[It] API errors returns 500 [smoke]", Status: "failed"},
+				{Name: "[AfterSuite] cleanup", Status: "passed"},
+			},
+		}}}
+
+		cases := NormalizeJunitReport(junitReport, nil, "/data/repo").TestSuites[0].TestCases
+		assert.Equal(t, "repo", cases[0].Classname)
+		assert.Equal(t, "API errors returns 500", cases[0].Name)
+		assert.Equal(t, "smoke", cases[0].Properties.Properties[0].Value)
+		assert.Equal(t, "AfterSuite cleanup", cases[1].Name)
+	})
+
+	t.Run("NormalizeJunitReportFile should rewrite the report and keep the original one outside of reports", func(t *testing.T) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "reports", "report.xml")
+		assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		assert.NoError(t, reporters.GenerateJUnitReport(report, path))
+
+		originalPath, err := NormalizeJunitReportFile(path, filepath.Join(dir, "junit"), []types.Report{report}, "/data/repo")
+		assert.NoError(t, err)
+		assert.Equal(t, filepath.Join(dir, "junit", "report.xml"), originalPath)
+
+		// only the normalized report is scraped
+		entries, err := os.ReadDir(filepath.Join(dir, "reports"))
+		assert.NoError(t, err)
+		assert.Len(t, entries, 1)
+
+		data, err := os.ReadFile(path)
+		assert.NoError(t, err)
+		assert.Contains(t, string(data), `<testcase name="returns 500" classname="e2e.api.API.errors" status="failed"`)
+		assert.Contains(t, string(data), `<property name="label" value="smoke"></property>`)
+
+		// steps keep the names mapped from the report written by ginkgo
+		suites, err := junit.IngestFile(originalPath)
+		assert.NoError(t, err)
+		result := MapJunitToExecutionResults(nil, suites)
+		assert.NotNil(t, FindStep(&result, SpecStepName(report, report.SpecReports[1])))
+
+		suites, err = junit.IngestFile(path)
+		assert.NoError(t, err)
+		assert.Equal(t, "e2e.api.API.errors", suites[0].Tests[1].Classname)
+	})
+}
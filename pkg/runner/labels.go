@@ -0,0 +1,124 @@
+package runner
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/kubeshop/testkube/pkg/api/v1/testkube"
+	"github.com/kubeshop/testkube/pkg/executor/output"
+	"github.com/kubeshop/testkube/pkg/process"
+	"github.com/kubeshop/testkube/pkg/ui"
+	"github.com/onsi/ginkgo/v2/types"
+)
+
+const noLabel = "(no label)"
+
+// LabelResults are the spec results of a single label
+type LabelResults struct {
+	Label   string
+	Passed  int
+	Failed  int
+	Skipped int
+}
+
+// AddSpecLabels adds the labels of every spec to its step
+func AddSpecLabels(result *testkube.ExecutionResult, reports []types.Report) {
+	for _, report := range reports {
+		for _, spec := range report.SpecReports {
+			labels := spec.Labels()
+			if len(labels) == 0 {
+				continue
+			}
+
+			step := FindStep(result, SpecStepName(report, spec))
+			if step == nil {
+				continue
+			}
+
+			step.AssertionResults = append(step.AssertionResults, testkube.AssertionResult{
+				Name:   "labels: " + strings.Join(labels, ", "),
+				Status: string(testkube.PASSED_ExecutionStatus),
+			})
+		}
+	}
+}
+
+// ResultsByLabel returns passed, failed and skipped specs per label, specs without labels are counted under "(no label)"
+func ResultsByLabel(reports []types.Report) []LabelResults {
+	byLabel := make(map[string]*LabelResults)
+	for _, report := range reports {
+		for _, spec := range report.SpecReports {
+			if spec.LeafNodeType != types.NodeTypeIt {
+				continue
+			}
+
+			labels := spec.Labels()
+			if len(labels) == 0 {
+				labels = []string{noLabel}
+			}
+
+			for _, label := range labels {
+				results, found := byLabel[label]
+				if !found {
+					results = &LabelResults{Label: label}
+					byLabel[label] = results
+				}
+
+				switch {
+				case spec.Failed():
+					results.Failed++
+				case spec.State.Is(types.SpecStatePassed):
+					results.Passed++
+				default:
+					results.Skipped++
+				}
+			}
+		}
+	}
+
+	results := []LabelResults{}
+	for _, r := range byLabel {
+		results = append(results, *r)
+	}
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Label < results[j].Label
+	})
+
+	return results
+}
+
+// LabelResultsSummary renders results per label as appended to the execution output
+func LabelResultsSummary(results []LabelResults) string {
+	b := strings.Builder{}
+	b.WriteString("Results by label:\n")
+	for _, r := range results {
+		b.WriteString(fmt.Sprintf("  %s: %d passed, %d failed, %d skipped\n", r.Label, r.Passed, r.Failed, r.Skipped))
+	}
+
+	return b.String()
+}
+
+// ListLabels returns the output of the ginkgo labels command for the packages selected by params
+func ListLabels(bin, path, runPath string, params map[string]string) (string, error) {
+	output.PrintLog(fmt.Sprintf("%s Listing labels with ginkgo labels", ui.IconWorld))
+
+	args := []string{"labels"}
+	if params["GinkgoRecursive"] != "" {
+		args = append(args, params["GinkgoRecursive"])
+	}
+
+	// reuse package resolution of the run itself
+	packages, err := BuildGinkgoArgs(map[string]string{"GinkgoTestPackage": params["GinkgoTestPackage"]}, path, runPath)
+	if err != nil {
+		return "", err
+	}
+	args = append(args, packages...)
+
+	out, err := process.ExecuteInDir(runPath, bin, args...)
+	if err != nil {
+		return "", fmt.Errorf("could not list labels: %w", err)
+	}
+
+	return string(out), nil
+}
@@ -0,0 +1,47 @@
+package runner
+
+import (
+	"testing"
+
+	"github.com/kubeshop/testkube/pkg/api/v1/testkube"
+	"github.com/onsi/ginkgo/v2/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLabels(t *testing.T) {
+	reports := []types.Report{{
+		SuiteDescription: "E2E Suite",
+		SpecReports: types.SpecReports{
+			{LeafNodeType: types.NodeTypeBeforeSuite, State: types.SpecStatePassed},
+			newSpecReport([]string{"API"}, "responds", types.SpecStatePassed, "smoke", "network"),
+			newSpecReport([]string{"API"}, "times out", types.SpecStateFailed, "network"),
+			newSpecReport([]string{"API"}, "is slow", types.SpecStateSkipped, "slow"),
+			newSpecReport([]string{"API"}, "has no label", types.SpecStatePending),
+		},
+	}}
+
+	t.Run("ResultsByLabel should count passed, failed and skipped specs per label", func(t *testing.T) {
+		assert.Equal(t, []LabelResults{
+			{Label: "(no label)", Skipped: 1},
+			{Label: "network", Passed: 1, Failed: 1},
+			{Label: "slow", Skipped: 1},
+			{Label: "smoke", Passed: 1},
+		}, ResultsByLabel(reports))
+	})
+
+	t.Run("AddSpecLabels should add labels to steps of specs", func(t *testing.T) {
+		result := testkube.ExecutionResult{Steps: []testkube.ExecutionStepResult{
+			{Name: "E2E Suite - [It] API responds [smoke, network]", Status: "passed"},
+			{Name: "E2E Suite - [It] API has no label", Status: "skipped"},
+		}}
+
+		AddSpecLabels(&result, reports)
+		assert.Equal(t, []testkube.AssertionResult{{Name: "labels: smoke, network", Status: "passed"}}, result.Steps[0].AssertionResults)
+		assert.Empty(t, result.Steps[1].AssertionResults)
+	})
+
+	t.Run("LabelResultsSummary should render a line per label", func(t *testing.T) {
+		summary := LabelResultsSummary([]LabelResults{{Label: "network", Passed: 1, Failed: 2, Skipped: 3}})
+		assert.Equal(t, "Results by label:\n  network: 1 passed, 2 failed, 3 skipped\n", summary)
+	})
+}
@@ -0,0 +1,135 @@
+package runner
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/kubeshop/testkube/pkg/api/v1/testkube"
+	"github.com/onsi/ginkgo/v2/types"
+)
+
+// RenderMarkdownSummary renders a summary of the mapped results ready to be posted as a PR comment: totals,
+// failed specs with messages and locations from the JSON report, slowest specs and coverage
+func RenderMarkdownSummary(result testkube.ExecutionResult, reports []types.Report, slowest []SpecTiming, coverage *CoverageSummary) string {
+	passed, failed, skipped := markdownTotals(result, reports)
+
+	status := "unknown"
+	if result.Status != nil {
+		status = string(*result.Status)
+	}
+
+	b := strings.Builder{}
+	b.WriteString("## Ginkgo test results\n\n")
+	b.WriteString(fmt.Sprintf("**Status:** %s\n\n", status))
+	if result.ErrorMessage != "" {
+		b.WriteString(fmt.Sprintf("**Error:** %s\n\n", firstLine(result.ErrorMessage)))
+	}
+	b.WriteString("| Total | Passed | Failed | Skipped |\n|---:|---:|---:|---:|\n")
+	b.WriteString(fmt.Sprintf("| %d | %d | %d | %d |\n", passed+failed+skipped, passed, failed, skipped))
+
+	failures := markdownFailures(result, reports)
+	if len(failures) > 0 {
+		b.WriteString(fmt.Sprintf("\n### Failures (%d)\n\n", len(failures)))
+		for _, failure := range failures {
+			b.WriteString(failure)
+		}
+	}
+
+	if len(slowest) > 0 {
+		b.WriteString("\n### Slowest specs\n\n| Spec | Duration |\n|---|---:|\n")
+		for _, timing := range slowest {
+			b.WriteString(fmt.Sprintf("| %s | %s |\n", markdownCell(timing.Step), timing.Duration))
+		}
+	}
+
+	if coverage != nil {
+		b.WriteString(fmt.Sprintf("\n### Coverage: %.1f%%\n\n| Package | Coverage |\n|---|---:|\n", coverage.Total.Percent()))
+		for _, pkg := range coverage.Packages {
+			b.WriteString(fmt.Sprintf("| %s | %.1f%% |\n", markdownCell(pkg.Package), pkg.Percent()))
+		}
+	}
+
+	return b.String()
+}
+
+// WriteMarkdownSummary writes the Markdown summary to path
+func WriteMarkdownSummary(summary, path string) error {
+	return os.WriteFile(path, []byte(summary), 0644)
+}
+
+// markdownTotals counts specs and failed suite nodes in the JSON report the way the HTML report does, skipped
+// and pending specs are mapped to failed steps, so steps are only counted when there's no JSON report
+func markdownTotals(result testkube.ExecutionResult, reports []types.Report) (passed, failed, skipped int) {
+	if len(reports) == 0 {
+		for _, step := range result.Steps {
+			if step.Status == string(testkube.PASSED_ExecutionStatus) {
+				passed++
+			} else {
+				failed++
+			}
+		}
+		return passed, failed, skipped
+	}
+
+	for _, report := range reports {
+		for _, spec := range report.SpecReports {
+			if spec.LeafNodeType != types.NodeTypeIt && !spec.Failed() {
+				continue
+			}
+
+			switch {
+			case spec.Failed():
+				failed++
+			case spec.State == types.SpecStatePassed:
+				passed++
+			default:
+				skipped++
+			}
+		}
+	}
+
+	return passed, failed, skipped
+}
+
+// markdownFailures returns list items of failed steps, with message and location when the spec is in the JSON report
+func markdownFailures(result testkube.ExecutionResult, reports []types.Report) []string {
+	details := make(map[string]types.SpecReport)
+	for _, report := range reports {
+		for _, spec := range report.SpecReports {
+			if spec.Failed() {
+				details[SpecStepName(report, spec)] = spec
+			}
+		}
+	}
+
+	failures := []string{}
+	for _, step := range result.Steps {
+		if step.Status == string(testkube.PASSED_ExecutionStatus) {
+			continue
+		}
+
+		spec, found := details[step.Name]
+		if !found {
+			// specs skipped because of a failed suite node are failed steps too, they're named by the suite failure
+			if len(reports) > 0 {
+				continue
+			}
+			failures = append(failures, fmt.Sprintf("- **%s**\n", step.Name))
+			continue
+		}
+
+		item := fmt.Sprintf("- **%s** at `%s`\n", step.Name, spec.Failure.Location)
+		if message := strings.TrimSpace(spec.Failure.Message); message != "" {
+			item += "  ```\n  " + strings.ReplaceAll(message, "\n", "\n  ") + "\n  ```\n"
+		}
+		failures = append(failures, item)
+	}
+
+	return failures
+}
+
+// markdownCell escapes pipes in table cells
+func markdownCell(text string) string {
+	return strings.ReplaceAll(text, "|", `\|`)
+}
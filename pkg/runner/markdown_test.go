@@ -0,0 +1,61 @@
+package runner
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kubeshop/testkube/pkg/api/v1/testkube"
+	"github.com/onsi/ginkgo/v2/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarkdownSummary(t *testing.T) {
+	failed := newSpecReport([]string{"API"}, "returns 500", types.SpecStateFailed)
+	failed.Failure = types.Failure{
+		Message:  "Expected\n    <int>: 200\nto equal\n    <int>: 500",
+		Location: types.CodeLocation{FileName: "/data/repo/e2e/url_test.go", LineNumber: 21},
+	}
+	reports := []types.Report{{
+		SuiteDescription: "E2E Suite",
+		SpecReports: types.SpecReports{
+			newSpecReport([]string{"API"}, "responds", types.SpecStatePassed),
+			failed,
+			newSpecReport([]string{"API"}, "is pending", types.SpecStatePending),
+		},
+	}}
+
+	// skipped and pending specs are mapped to failed steps
+	result := testkube.ExecutionResult{Steps: []testkube.ExecutionStepResult{
+		{Name: "E2E Suite - [It] API responds", Status: "passed"},
+		{Name: "E2E Suite - [It] API returns 500", Status: "failed"},
+		{Name: "E2E Suite - [It] API is pending", Status: "failed"},
+	}}
+	result.Err(assert.AnError)
+
+	t.Run("RenderMarkdownSummary should render totals, failures, slowest specs and coverage", func(t *testing.T) {
+		slowest := []SpecTiming{{Step: "E2E Suite - [It] API | responds", Duration: 3 * time.Second}}
+		coverage := &CoverageSummary{
+			Total:    PackageCoverage{Package: "total", Statements: 4, Covered: 3},
+			Packages: []PackageCoverage{{Package: "example.com/api", Statements: 4, Covered: 3}},
+		}
+
+		summary := RenderMarkdownSummary(result, reports, slowest, coverage)
+		assert.Equal(t, "## Ginkgo test results\n\n"+
+			"**Status:** failed\n\n"+
+			"**Error:** "+assert.AnError.Error()+"\n\n"+
+			"| Total | Passed | Failed | Skipped |\n|---:|---:|---:|---:|\n| 3 | 1 | 1 | 1 |\n"+
+			"\n### Failures (1)\n\n"+
+			"- **E2E Suite - [It] API returns 500** at `/data/repo/e2e/url_test.go:21`\n"+
+			"  ```\n  Expected\n      <int>: 200\n  to equal\n      <int>: 500\n  ```\n"+
+			"\n### Slowest specs\n\n| Spec | Duration |\n|---|---:|\n| E2E Suite - [It] API \\| responds | 3s |\n"+
+			"\n### Coverage: 75.0%\n\n| Package | Coverage |\n|---|---:|\n| example.com/api | 75.0% |\n", summary)
+	})
+
+	t.Run("RenderMarkdownSummary should list failed steps without JSON report", func(t *testing.T) {
+		summary := RenderMarkdownSummary(result, nil, nil, nil)
+		assert.Contains(t, summary, "| 3 | 1 | 2 | 0 |\n")
+		assert.Contains(t, summary, "- **E2E Suite - [It] API returns 500**\n")
+		assert.NotContains(t, summary, "### Slowest specs")
+		assert.NotContains(t, summary, "### Coverage")
+	})
+}
@@ -0,0 +1,87 @@
+package runner
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ExecutorOptions are executor only params parsed before the tests run, so invalid values fail the execution
+// right away instead of after the whole suite ran
+type ExecutorOptions struct {
+	// CoverageThreshold is the minimal total statement coverage in percent, negative when not checked
+	CoverageThreshold float64
+	// CoveragePackageThreshold is the minimal statement coverage of every package in percent, negative when not checked
+	CoveragePackageThreshold float64
+	// FlakyThreshold is the number of flaky specs allowed, negative when not checked
+	FlakyThreshold int
+	// SlowestSpecs is the number of slowest specs summarised in the output
+	SlowestSpecs int
+	// Upload configures how scraped artifacts are packaged
+	Upload UploadOptions
+}
+
+// ParseExecutorOptions reads GinkgoCoverageThreshold, GinkgoCoveragePackageThreshold, GinkgoFlakyThreshold,
+// GinkgoSlowestSpecs and artifact upload params, all invalid values are reported at once
+func ParseExecutorOptions(params map[string]string) (ExecutorOptions, error) {
+	options := ExecutorOptions{CoverageThreshold: -1, CoveragePackageThreshold: -1, FlakyThreshold: -1}
+	problems := []string{}
+	var err error
+
+	if options.CoverageThreshold, err = parsePercent(params["GinkgoCoverageThreshold"]); err != nil {
+		problems = append(problems, fmt.Sprintf("invalid coverage threshold %q", params["GinkgoCoverageThreshold"]))
+	}
+	if options.CoveragePackageThreshold, err = parsePercent(params["GinkgoCoveragePackageThreshold"]); err != nil {
+		problems = append(problems, fmt.Sprintf("invalid package coverage threshold %q", params["GinkgoCoveragePackageThreshold"]))
+	}
+
+	if threshold := params["GinkgoFlakyThreshold"]; threshold != "" {
+		if options.FlakyThreshold, err = strconv.Atoi(threshold); err != nil || options.FlakyThreshold < 0 {
+			problems = append(problems, fmt.Sprintf("invalid flaky specs threshold %q", threshold))
+		}
+	}
+
+	if options.SlowestSpecs, err = ParseSlowestSpecs(params["GinkgoSlowestSpecs"]); err != nil {
+		problems = append(problems, err.Error())
+	}
+
+	if options.Upload, err = ParseUploadOptions(params); err != nil {
+		problems = append(problems, err.Error())
+	}
+
+	if len(problems) > 0 {
+		return options, fmt.Errorf("invalid executor params: %s", strings.Join(problems, "; "))
+	}
+
+	return options, nil
+}
+
+// parsePercent reads a percentage like 80 or 80%, empty value is -1
+func parsePercent(value string) (float64, error) {
+	if value == "" {
+		return -1, nil
+	}
+
+	percent, err := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(value), "%"), 64)
+	if err != nil || percent < 0 || percent > 100 {
+		return -1, fmt.Errorf("invalid percentage %q", value)
+	}
+
+	return percent, nil
+}
+
+// combineErrors returns an error with messages of all errors which are not nil, or nil if there are none
+func combineErrors(errs ...error) error {
+	messages := []string{}
+	for _, err := range errs {
+		if err != nil {
+			messages = append(messages, err.Error())
+		}
+	}
+
+	if len(messages) == 0 {
+		return nil
+	}
+
+	return fmt.Errorf("%s", strings.Join(messages, "; "))
+}
@@ -0,0 +1,45 @@
+package runner
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOptions(t *testing.T) {
+	t.Run("ParseExecutorOptions should read thresholds and leave unset ones unchecked", func(t *testing.T) {
+		options, err := ParseExecutorOptions(map[string]string{
+			"GinkgoCoverageThreshold":   "80%",
+			"GinkgoFlakyThreshold":      "2",
+			"GinkgoSlowestSpecs":        "10",
+			"GinkgoArtifactMaxFileSize": "10MB",
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, ExecutorOptions{CoverageThreshold: 80, CoveragePackageThreshold: -1, FlakyThreshold: 2, SlowestSpecs: 10,
+			Upload: UploadOptions{MaxFileSize: 10 << 20}}, options)
+	})
+
+	t.Run("ParseExecutorOptions should report all invalid values at once", func(t *testing.T) {
+		_, err := ParseExecutorOptions(map[string]string{
+			"GinkgoCoverageThreshold":        "eighty",
+			"GinkgoCoveragePackageThreshold": "120",
+			"GinkgoFlakyThreshold":           "x",
+			"GinkgoSlowestSpecs":             "abc",
+			"GinkgoArtifactMaxFileSize":      "10XB",
+			"GinkgoArtifactMaxTotalSize":     "-1MB",
+		})
+		assert.ErrorContains(t, err, `invalid coverage threshold "eighty"`)
+		assert.ErrorContains(t, err, `invalid package coverage threshold "120"`)
+		assert.ErrorContains(t, err, `invalid flaky specs threshold "x"`)
+		assert.ErrorContains(t, err, `invalid number of slowest specs "abc"`)
+		assert.ErrorContains(t, err, `artifact file size limit: invalid size "10XB"`)
+		assert.ErrorContains(t, err, `artifact total size limit: invalid size "-1MB"`)
+	})
+
+	t.Run("combineErrors should keep messages of all errors", func(t *testing.T) {
+		assert.NoError(t, combineErrors(nil, nil))
+		assert.EqualError(t, combineErrors(errors.New("exit status 1"), nil, errors.New("coverage threshold not met")),
+			"exit status 1; coverage threshold not met")
+	})
+}
@@ -0,0 +1,186 @@
+package runner
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/kubeshop/testkube/pkg/api/v1/testkube"
+	"github.com/kubeshop/testkube/pkg/executor/env"
+	"github.com/kubeshop/testkube/pkg/executor/output"
+	"github.com/kubeshop/testkube/pkg/ui"
+	"github.com/onsi/ginkgo/v2/types"
+)
+
+// SpecProgress are the progress reports emitted while a spec was running
+type SpecProgress struct {
+	Step    string
+	Reports []types.ProgressReport
+	// Interrupted is set when the last report was emitted because the spec timed out or was interrupted
+	Interrupted bool
+}
+
+// FindSpecProgress returns specs with progress reports, polled ones as well as the one ginkgo emits
+// when a spec or the suite times out
+func FindSpecProgress(reports []types.Report) []SpecProgress {
+	progress := []SpecProgress{}
+	for _, report := range reports {
+		for _, spec := range report.SpecReports {
+			specProgress := SpecProgress{
+				Step:    SpecStepName(report, spec),
+				Reports: append([]types.ProgressReport{}, spec.ProgressReports...),
+			}
+
+			if !spec.Failure.ProgressReport.IsZero() {
+				specProgress.Reports = append(specProgress.Reports, spec.Failure.ProgressReport)
+				specProgress.Interrupted = true
+			}
+
+			if len(specProgress.Reports) > 0 {
+				progress = append(progress, specProgress)
+			}
+		}
+	}
+
+	return progress
+}
+
+// AttachProgressReports adds progress reports to the steps of their specs, the report of an interrupted spec is failed
+func AttachProgressReports(result *testkube.ExecutionResult, progress []SpecProgress) {
+	for _, spec := range progress {
+		step := FindStep(result, spec.Step)
+		if step == nil {
+			continue
+		}
+
+		for i, report := range spec.Reports {
+			status := testkube.PASSED_ExecutionStatus
+			if spec.Interrupted && i == len(spec.Reports)-1 {
+				status = testkube.FAILED_ExecutionStatus
+			}
+
+			step.AssertionResults = append(step.AssertionResults, testkube.AssertionResult{
+				Name:         fmt.Sprintf("progress report at %s", progressLocation(report)),
+				Status:       string(status),
+				ErrorMessage: RenderProgressReport(report),
+			})
+		}
+	}
+}
+
+// ProgressReportsSummary renders specs with progress reports as appended to the execution output
+func ProgressReportsSummary(progress []SpecProgress) string {
+	b := strings.Builder{}
+	b.WriteString(fmt.Sprintf("Specs with progress reports: %d\n", len(progress)))
+	for _, spec := range progress {
+		last := spec.Reports[len(spec.Reports)-1]
+		b.WriteString(fmt.Sprintf("  %s: %d reports, last at %s\n", spec.Step, len(spec.Reports), progressLocation(last)))
+	}
+
+	return b.String()
+}
+
+// RenderProgressReport renders the current node and step of a progress report with the spec goroutine
+// and goroutines highlighted by ginkgo
+func RenderProgressReport(report types.ProgressReport) string {
+	b := strings.Builder{}
+	if report.Message != "" {
+		b.WriteString(report.Message + "\n")
+	}
+
+	b.WriteString(fmt.Sprintf("In [%s] %s at %s", report.CurrentNodeType, report.CurrentNodeText, report.CurrentNodeLocation))
+	if !report.CurrentNodeStartTime.IsZero() && !report.Time().IsZero() {
+		b.WriteString(fmt.Sprintf(" (running for %s)", report.Time().Sub(report.CurrentNodeStartTime).Round(time.Millisecond)))
+	}
+	b.WriteString("\n")
+
+	if report.CurrentStepText != "" {
+		b.WriteString(fmt.Sprintf("At [By Step] %s at %s\n", report.CurrentStepText, report.CurrentStepLocation))
+	}
+
+	goroutines := report.HighlightedGoroutines()
+	if spec := report.SpecGoroutine(); !spec.IsZero() {
+		goroutines = append([]types.Goroutine{spec}, goroutines...)
+	}
+
+	for _, goroutine := range goroutines {
+		b.WriteString(fmt.Sprintf("\ngoroutine %d [%s]\n", goroutine.ID, goroutine.State))
+		for _, call := range goroutine.Stack {
+			marker := " "
+			if call.Highlight {
+				marker = ">"
+			}
+			b.WriteString(fmt.Sprintf("%s %s\n    %s:%d\n", marker, call.Function, call.Filename, call.Line))
+		}
+	}
+
+	return strings.TrimSpace(b.String())
+}
+
+// progressLocation returns the location of the current step or node of a progress report
+func progressLocation(report types.ProgressReport) string {
+	if report.CurrentStepText != "" {
+		return report.CurrentStepLocation.String()
+	}
+
+	return report.CurrentNodeLocation.String()
+}
+
+// RunGinkgo runs ginkgo the same way executor.Run does and interrupts it when the execution is terminated, e.g. when
+// the Testkube test times out. Ginkgo and the suites it runs get their own process group, which SIGTERM received by
+// the executor is forwarded to as an interrupt, the same way Ctrl+C in a terminal does, so ginkgo reports progress of
+// the running specs and writes its reports, which are mapped and scraped before the executor is killed.
+func RunGinkgo(dir, bin string, envManager env.Interface, arguments ...string) (out []byte, err error) {
+	obfuscatedArgs := envManager.ObfuscateSecrets([]byte(strings.Join(arguments, " ")))
+	output.PrintLog(fmt.Sprintf("%s Executing in directory %s: \n $ %s %s", ui.IconMicroscope, dir, bin, obfuscatedArgs))
+
+	buffer := new(bytes.Buffer)
+	writer := io.MultiWriter(buffer, output.NewJSONWrapWriter(os.Stdout, envManager))
+	cmd := exec.Command(bin, arguments...)
+	cmd.Dir = dir
+	cmd.Stdout = writer
+	cmd.Stderr = writer
+	setProcessGroup(cmd)
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, terminationSignals...)
+	defer signal.Stop(signals)
+
+	if err = cmd.Start(); err != nil {
+		err = fmt.Errorf("could not start process: %w", err)
+	} else {
+		done := make(chan struct{})
+		go forwardTermination(signals, done, func() error { return interruptProcessGroup(cmd.Process.Pid) })
+		if err = cmd.Wait(); err != nil {
+			err = fmt.Errorf("process error: %w", err)
+		}
+		close(done)
+	}
+
+	if err != nil {
+		output.PrintLog(fmt.Sprintf("%s Execution failed: %s", ui.IconCross, err.Error()))
+		return buffer.Bytes(), err
+	}
+
+	output.PrintLog(fmt.Sprintf("%s Execution succeeded", ui.IconCheckMark))
+	return buffer.Bytes(), nil
+}
+
+// forwardTermination calls interrupt on the first signal and restores the default handling of termination signals,
+// so the executor can still be stopped if ginkgo doesn't finish in time
+func forwardTermination(signals <-chan os.Signal, done <-chan struct{}, interrupt func() error) {
+	select {
+	case <-done:
+	case sig := <-signals:
+		signal.Reset(terminationSignals...)
+		output.PrintLog(fmt.Sprintf("%s Received %s, interrupting ginkgo to collect progress reports and results", ui.IconWarning, sig))
+		if err := interrupt(); err != nil {
+			output.PrintLog(fmt.Sprintf("%s could not interrupt ginkgo: %s", ui.IconCross, err.Error()))
+		}
+	}
+}
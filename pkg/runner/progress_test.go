@@ -0,0 +1,135 @@
+package runner
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/kubeshop/testkube/pkg/api/v1/testkube"
+	"github.com/kubeshop/testkube/pkg/executor/env"
+	"github.com/onsi/ginkgo/v2/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProgress(t *testing.T) {
+	start := time.Date(2023, 4, 1, 10, 0, 0, 0, time.UTC)
+	polled := types.ProgressReport{
+		CurrentNodeType:      types.NodeTypeIt,
+		CurrentNodeText:      "responds",
+		CurrentNodeLocation:  types.CodeLocation{FileName: "/data/repo/e2e/url_test.go", LineNumber: 14},
+		CurrentNodeStartTime: start,
+		CurrentStepText:      "calling the API",
+		CurrentStepLocation:  types.CodeLocation{FileName: "/data/repo/e2e/url_test.go", LineNumber: 16},
+		TimelineLocation:     types.TimelineLocation{Time: start.Add(30 * time.Second)},
+		Goroutines: []types.Goroutine{
+			{ID: 7, State: "select", IsSpecGoroutine: true, Stack: []types.FunctionCall{
+				{Function: "net/http.(*Client).Get", Filename: "/usr/local/go/src/net/http/client.go", Line: 480},
+				{Function: "e2e.glob..func1.1()", Filename: "/data/repo/e2e/url_test.go", Line: 17, Highlight: true},
+			}},
+			{ID: 8, State: "IO wait", Stack: []types.FunctionCall{{Function: "internal/poll.runtime_pollWait", Filename: "netpoll.go", Line: 305}}},
+		},
+	}
+	interrupted := polled
+	interrupted.Message = "Spec timed out"
+
+	hung := newSpecReport([]string{"API"}, "responds", types.SpecStateTimedout)
+	hung.ProgressReports = []types.ProgressReport{polled}
+	hung.Failure = types.Failure{Message: "A suite timeout occurred", ProgressReport: interrupted}
+
+	reports := []types.Report{{
+		SuiteDescription: "E2E Suite",
+		SpecReports: types.SpecReports{
+			hung,
+			newSpecReport([]string{"API"}, "is quick", types.SpecStatePassed),
+		},
+	}}
+
+	t.Run("FindSpecProgress should return polled and interrupt progress reports", func(t *testing.T) {
+		progress := FindSpecProgress(reports)
+		assert.Len(t, progress, 1)
+		assert.Equal(t, "E2E Suite - [It] API responds", progress[0].Step)
+		assert.Len(t, progress[0].Reports, 2)
+		assert.True(t, progress[0].Interrupted)
+	})
+
+	t.Run("RenderProgressReport should render current step and highlighted goroutines", func(t *testing.T) {
+		rendered := RenderProgressReport(interrupted)
+		assert.Equal(t, "Spec timed out\n"+
+			"In [It] responds at /data/repo/e2e/url_test.go:14 (running for 30s)\n"+
+			"At [By Step] calling the API at /data/repo/e2e/url_test.go:16\n"+
+			"\n"+
+			"goroutine 7 [select]\n"+
+			"  net/http.(*Client).Get\n"+
+			"    /usr/local/go/src/net/http/client.go:480\n"+
+			"> e2e.glob..func1.1()\n"+
+			"    /data/repo/e2e/url_test.go:17", rendered)
+	})
+
+	t.Run("AttachProgressReports should fail the report of an interrupted spec", func(t *testing.T) {
+		result := testkube.ExecutionResult{Steps: []testkube.ExecutionStepResult{
+			{Name: "E2E Suite - [It] API responds", Status: "failed"},
+		}}
+
+		progress := FindSpecProgress(reports)
+		AttachProgressReports(&result, progress)
+		assertions := result.Steps[0].AssertionResults
+		assert.Len(t, assertions, 2)
+		assert.Equal(t, "progress report at /data/repo/e2e/url_test.go:16", assertions[0].Name)
+		assert.Equal(t, "passed", assertions[0].Status)
+		assert.Equal(t, "failed", assertions[1].Status)
+
+		assert.Equal(t, "Specs with progress reports: 1\n  E2E Suite - [It] API responds: 2 reports, last at /data/repo/e2e/url_test.go:16\n", ProgressReportsSummary(progress))
+	})
+	t.Run("forwardTermination should interrupt ginkgo once and stop handling signals", func(t *testing.T) {
+		signals := make(chan os.Signal, 2)
+		done := make(chan struct{})
+		interrupted := make(chan struct{}, 2)
+		finished := make(chan struct{})
+		go func() {
+			forwardTermination(signals, done, func() error {
+				interrupted <- struct{}{}
+				return nil
+			})
+			close(finished)
+		}()
+
+		signals <- syscall.SIGTERM
+		<-finished
+		signals <- syscall.SIGTERM
+		assert.Len(t, interrupted, 1)
+		assert.Len(t, signals, 1)
+	})
+
+	t.Run("RunGinkgo should interrupt only the process group of ginkgo on termination", func(t *testing.T) {
+		if runtime.GOOS == "windows" {
+			t.Skip("interrupting ginkgo is not supported on windows")
+		}
+
+		// the script stands for ginkgo and a suite it runs, both report the interrupt
+		dir := t.TempDir()
+		script := filepath.Join(dir, "ginkgo")
+		assert.NoError(t, os.WriteFile(script, []byte("#!/bin/sh\n"+
+			"trap 'echo ginkgo interrupted' INT\n"+
+			"sh -c 'trap \"echo suite interrupted; exit 1\" INT; touch started; sleep 5 >/dev/null 2>&1 & wait'\n"+
+			"exit 0\n"), 0755))
+
+		go func() {
+			for {
+				if _, err := os.Stat(filepath.Join(dir, "started")); err == nil {
+					break
+				}
+				time.Sleep(10 * time.Millisecond)
+			}
+			process, _ := os.FindProcess(os.Getpid())
+			process.Signal(syscall.SIGTERM)
+		}()
+
+		out, err := RunGinkgo(dir, script, env.NewManager())
+		assert.NoError(t, err)
+		assert.Contains(t, string(out), "ginkgo interrupted")
+		assert.Contains(t, string(out), "suite interrupted")
+	})
+}
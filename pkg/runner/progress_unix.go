@@ -0,0 +1,22 @@
+//go:build !windows
+
+package runner
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+// terminationSignals are forwarded to ginkgo
+var terminationSignals = []os.Signal{syscall.SIGTERM, syscall.SIGINT}
+
+// setProcessGroup starts ginkgo in its own process group, which the suites it runs inherit
+func setProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+}
+
+// interruptProcessGroup interrupts ginkgo and the suites it runs, but not the executor
+func interruptProcessGroup(pid int) error {
+	return syscall.Kill(-pid, syscall.SIGINT)
+}
@@ -0,0 +1,18 @@
+//go:build windows
+
+package runner
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+)
+
+// terminationSignals are forwarded to ginkgo, on Windows the console delivers the interrupt to ginkgo itself
+var terminationSignals = []os.Signal{os.Interrupt}
+
+func setProcessGroup(cmd *exec.Cmd) {}
+
+func interruptProcessGroup(pid int) error {
+	return errors.New("interrupting ginkgo is not supported on windows")
+}
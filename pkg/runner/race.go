@@ -0,0 +1,165 @@
+package runner
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	junit "github.com/joshdk/go-junit"
+	"github.com/kubeshop/testkube/pkg/api/v1/testkube"
+	"github.com/kubeshop/testkube/pkg/executor/output"
+	"github.com/kubeshop/testkube/pkg/ui"
+)
+
+const (
+	dataRaceHeader    = "WARNING: DATA RACE"
+	dataRaceSeparator = "=================="
+)
+
+// DataRace is a single report of the go race detector
+type DataRace struct {
+	Sections []RaceSection
+	Raw      string
+}
+
+// RaceSection is an access or goroutine creation stack of a data race report
+type RaceSection struct {
+	Header string
+	Frames []StackFrame
+}
+
+// StackFrame is a single frame of a goroutine stack
+type StackFrame struct {
+	Function string
+	File     string
+	Line     int
+}
+
+// Location returns file:line of the top frame of the first racing access
+func (r DataRace) Location() string {
+	for _, section := range r.Sections {
+		if len(section.Frames) > 0 {
+			return fmt.Sprintf("%s:%d", section.Frames[0].File, section.Frames[0].Line)
+		}
+	}
+
+	return "unknown location"
+}
+
+// ParseDataRaces extracts data race reports written by the race detector from the output, reports repeated
+// by ginkgo when it prints the captured output of a failed spec are only returned once
+func ParseDataRaces(out string) []DataRace {
+	races := []DataRace{}
+	seen := make(map[string]bool)
+	lines := strings.Split(out, "\n")
+	for i := 0; i < len(lines); i++ {
+		if strings.TrimSpace(lines[i]) != dataRaceHeader {
+			continue
+		}
+
+		// captured output is indented in ginkgo failure reports
+		indent := lines[i][:len(lines[i])-len(strings.TrimLeft(lines[i], " \t"))]
+		raw := []string{dataRaceHeader}
+		race := DataRace{}
+		var section *RaceSection
+		for i++; i < len(lines) && strings.TrimSpace(lines[i]) != dataRaceSeparator; i++ {
+			line := strings.TrimRight(strings.TrimPrefix(lines[i], indent), "\r")
+			raw = append(raw, line)
+			trimmed := strings.TrimSpace(line)
+
+			switch {
+			case trimmed == "":
+				section = nil
+			case !strings.HasPrefix(line, " "):
+				race.Sections = append(race.Sections, RaceSection{Header: strings.TrimSuffix(trimmed, ":")})
+				section = &race.Sections[len(race.Sections)-1]
+			case section != nil && strings.HasPrefix(line, "      "):
+				if len(section.Frames) > 0 {
+					file, lineNumber := parseFrameLocation(trimmed)
+					section.Frames[len(section.Frames)-1].File = file
+					section.Frames[len(section.Frames)-1].Line = lineNumber
+				}
+			case section != nil:
+				section.Frames = append(section.Frames, StackFrame{Function: trimmed})
+			}
+		}
+
+		race.Raw = strings.TrimSpace(strings.Join(raw, "\n"))
+		if !seen[race.Raw] {
+			seen[race.Raw] = true
+			races = append(races, race)
+		}
+	}
+
+	return races
+}
+
+// AttachDataRaces adds data races found in the captured output of specs to their steps as failed assertions
+func AttachDataRaces(result *testkube.ExecutionResult, suites []junit.Suite) {
+	for _, suite := range suites {
+		for _, test := range suite.Tests {
+			races := ParseDataRaces(test.SystemOut + "\n" + test.SystemErr)
+			if len(races) == 0 {
+				continue
+			}
+
+			step := FindStep(result, StepName(suite.Name, test.Name))
+			if step == nil {
+				continue
+			}
+
+			for _, race := range races {
+				step.AssertionResults = append(step.AssertionResults, testkube.AssertionResult{
+					Name:         fmt.Sprintf("data race at %s", race.Location()),
+					Status:       string(testkube.FAILED_ExecutionStatus),
+					ErrorMessage: race.Raw,
+				})
+			}
+		}
+	}
+}
+
+// DataRacesSummary renders found data races as appended to the execution output
+func DataRacesSummary(races []DataRace) string {
+	b := strings.Builder{}
+	b.WriteString(fmt.Sprintf("Data races: %d\n", len(races)))
+	for _, race := range races {
+		b.WriteString(fmt.Sprintf("  at %s\n", race.Location()))
+		for _, section := range race.Sections {
+			b.WriteString(fmt.Sprintf("    %s\n", section.Header))
+		}
+	}
+
+	return b.String()
+}
+
+// CheckDataRaces fails when any data race was found
+func CheckDataRaces(races []DataRace) error {
+	if len(races) == 0 {
+		return nil
+	}
+
+	locations := []string{}
+	for _, race := range races {
+		locations = append(locations, race.Location())
+	}
+
+	output.PrintLog(fmt.Sprintf("%s %d data races detected", ui.IconCross, len(races)))
+	return fmt.Errorf("%d data races detected at: %s", len(races), strings.Join(locations, ", "))
+}
+
+// parseFrameLocation parses "/path/to/file.go:12 +0x3a" into file and line
+func parseFrameLocation(location string) (string, int) {
+	location = strings.Fields(location)[0]
+	i := strings.LastIndex(location, ":")
+	if i == -1 {
+		return location, 0
+	}
+
+	line, err := strconv.Atoi(location[i+1:])
+	if err != nil {
+		return location, 0
+	}
+
+	return location[:i], line
+}
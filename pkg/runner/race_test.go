@@ -0,0 +1,74 @@
+package runner
+
+import (
+	"testing"
+
+	junit "github.com/joshdk/go-junit"
+	"github.com/kubeshop/testkube/pkg/api/v1/testkube"
+	"github.com/stretchr/testify/assert"
+)
+
+const dataRaceOutput = `Running Suite: E2E Integration Testing Suite
+==================
+WARNING: DATA RACE
+Write at 0x00c0000a4010 by goroutine 8:
+  example.com/suite/e2e.glob..func1.1()
+      /data/repo/e2e/url_test.go:14 +0x44
+
+Previous read at 0x00c0000a4010 by goroutine 7:
+  example.com/suite/e2e.glob..func1()
+      /data/repo/e2e/url_test.go:18 +0x3a
+
+Goroutine 8 (running) created at:
+  example.com/suite/e2e.glob..func1()
+      /data/repo/e2e/url_test.go:12 +0x30
+==================
+`
+
+func TestRace(t *testing.T) {
+	t.Run("ParseDataRaces should extract goroutine stacks of data races", func(t *testing.T) {
+		races := ParseDataRaces(dataRaceOutput)
+		assert.Len(t, races, 1)
+		assert.Equal(t, "/data/repo/e2e/url_test.go:14", races[0].Location())
+		assert.Len(t, races[0].Sections, 3)
+		assert.Equal(t, "Previous read at 0x00c0000a4010 by goroutine 7", races[0].Sections[1].Header)
+		assert.Equal(t, StackFrame{
+			Function: "example.com/suite/e2e.glob..func1()",
+			File:     "/data/repo/e2e/url_test.go",
+			Line:     18,
+		}, races[0].Sections[1].Frames[0])
+	})
+
+	t.Run("ParseDataRaces should only return repeated indented reports once", func(t *testing.T) {
+		indented := ""
+		for _, line := range []string{"WARNING: DATA RACE", "Write at 0x00c0000a4010 by goroutine 8:", "  main.f()", "      /main.go:3 +0x1", "=================="} {
+			indented += "  " + line + "\n"
+		}
+
+		races := ParseDataRaces(indented + indented)
+		assert.Len(t, races, 1)
+		assert.Equal(t, "/main.go:3", races[0].Location())
+	})
+
+	t.Run("AttachDataRaces should add races to the step they occurred in", func(t *testing.T) {
+		suites := []junit.Suite{{
+			Name: "E2E Integration Testing Suite",
+			Tests: []junit.Test{
+				{Name: "[It] racy spec", Status: junit.StatusPassed, SystemOut: dataRaceOutput},
+				{Name: "[It] clean spec", Status: junit.StatusPassed},
+			},
+		}}
+		result := MapJunitToExecutionResults([]byte(dataRaceOutput), suites)
+		AttachDataRaces(&result, suites)
+
+		assert.Len(t, result.Steps[0].AssertionResults, 1)
+		assert.Equal(t, "data race at /data/repo/e2e/url_test.go:14", result.Steps[0].AssertionResults[0].Name)
+		assert.Equal(t, string(testkube.FAILED_ExecutionStatus), result.Steps[0].AssertionResults[0].Status)
+		assert.Empty(t, result.Steps[1].AssertionResults)
+	})
+
+	t.Run("CheckDataRaces should fail on any race", func(t *testing.T) {
+		assert.NoError(t, CheckDataRaces(nil))
+		assert.ErrorContains(t, CheckDataRaces(ParseDataRaces(dataRaceOutput)), "1 data races detected at: /data/repo/e2e/url_test.go:14")
+	})
+}
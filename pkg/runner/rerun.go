@@ -0,0 +1,271 @@
+package runner
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/kubeshop/testkube/pkg/executor/output"
+	"github.com/kubeshop/testkube/pkg/ui"
+	"github.com/onsi/ginkgo/v2/reporters"
+	"github.com/onsi/ginkgo/v2/types"
+)
+
+// LoadJunitReport reads a JUnit report in the format generated by ginkgo
+func LoadJunitReport(path string) (reporters.JUnitTestSuites, error) {
+	report := reporters.JUnitTestSuites{}
+	file, err := os.Open(path)
+	if err != nil {
+		return report, err
+	}
+	defer file.Close()
+
+	if err = xml.NewDecoder(file).Decode(&report); err != nil {
+		return report, fmt.Errorf("could not decode JUnit report %s: %w", path, err)
+	}
+
+	return report, nil
+}
+
+// WriteJunitReport writes a JUnit report the same way ginkgo does
+func WriteJunitReport(report reporters.JUnitTestSuites, path string) error {
+	return writeXMLReport(report, path)
+}
+
+// writeXMLReport writes an XML report with a header and indentation
+func writeXMLReport(report interface{}, path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	file.WriteString(xml.Header)
+	encoder := xml.NewEncoder(file)
+	encoder.Indent("  ", "    ")
+	if err = encoder.Encode(report); err != nil {
+		file.Close()
+		return err
+	}
+
+	return file.Close()
+}
+
+// FailedSpecTexts returns focus texts of specs that failed in a previous JSON or JUnit report. Specs skipped
+// because a suite setup node failed are returned as well, as they never ran.
+func FailedSpecTexts(path string) ([]string, error) {
+	if strings.HasSuffix(path, ".json") {
+		reports, err := LoadGinkgoReports(path)
+		if err != nil {
+			return nil, err
+		}
+
+		return failedSpecTextsFromGinkgoReports(reports), nil
+	}
+
+	report, err := LoadJunitReport(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return failedSpecTextsFromJunit(report), nil
+}
+
+// ArtifactReportPrefix marks a GinkgoRerunFailedFrom report uploaded as an artifact of a previous execution
+const ArtifactReportPrefix = "artifacts:"
+
+// ParseArtifactReport returns the execution ID and artifact name of a report referenced as
+// artifacts:<execution id>/<artifact name>, ok is false for paths
+func ParseArtifactReport(value string) (executionID, name string, ok bool, err error) {
+	if !strings.HasPrefix(value, ArtifactReportPrefix) {
+		return "", "", false, nil
+	}
+
+	executionID, name, found := strings.Cut(strings.TrimPrefix(value, ArtifactReportPrefix), "/")
+	if !found || executionID == "" || name == "" {
+		return "", "", true, fmt.Errorf("invalid artifact report %q, expected %s<execution id>/<artifact name>", value, ArtifactReportPrefix)
+	}
+
+	return executionID, name, true, nil
+}
+
+// FocusText returns the text ginkgo matches the focus against, the suite description followed by a space
+// and the full text of the spec
+func FocusText(suite, text string) string {
+	return suite + " " + text
+}
+
+// RerunFocus returns a focus param matching exactly the given focus texts. Whitespace is matched with \s
+// because params are split on spaces when passed to ginkgo.
+func RerunFocus(texts []string) string {
+	quoted := []string{}
+	for _, text := range texts {
+		quoted = append(quoted, strings.ReplaceAll(regexp.QuoteMeta(text), " ", `\s`))
+	}
+
+	return fmt.Sprintf(`--focus ^(?:%s)$`, strings.Join(quoted, "|"))
+}
+
+// MergeJunitReports updates the previous report with test cases that ran in the rerun, specs skipped
+// in the rerun keep their previous result, totals are recomputed
+func MergeJunitReports(previous, rerun reporters.JUnitTestSuites) reporters.JUnitTestSuites {
+	ran := make(map[string]reporters.JUnitTestCase)
+	for _, suite := range rerun.TestSuites {
+		for _, test := range suite.TestCases {
+			if test.Skipped == nil {
+				ran[suite.Name+"\x00"+test.Name] = test
+			}
+		}
+	}
+
+	merged := reporters.JUnitTestSuites{}
+	for _, suite := range previous.TestSuites {
+		suite.TestCases = append([]reporters.JUnitTestCase{}, suite.TestCases...)
+		for i, test := range suite.TestCases {
+			if rerunTest, found := ran[suite.Name+"\x00"+test.Name]; found {
+				suite.Time += rerunTest.Time - test.Time
+				suite.TestCases[i] = rerunTest
+			}
+		}
+
+		suite.Tests, suite.Skipped, suite.Disabled, suite.Failures, suite.Errors = 0, 0, 0, 0, 0
+		for _, test := range suite.TestCases {
+			suite.Tests++
+			switch {
+			case test.Skipped != nil && test.Skipped.Message == "pending":
+				suite.Disabled++
+			case test.Skipped != nil:
+				suite.Skipped++
+			case test.Error != nil:
+				suite.Errors++
+			case test.Failure != nil:
+				suite.Failures++
+			}
+		}
+
+		merged.Tests += suite.Tests
+		merged.Disabled += suite.Disabled + suite.Skipped
+		merged.Errors += suite.Errors
+		merged.Failures += suite.Failures
+		merged.Time += suite.Time
+		merged.TestSuites = append(merged.TestSuites, suite)
+	}
+
+	return merged
+}
+
+// CombineRerunReport merges the JUnit report of the rerun into the previous JSON or JUnit report
+// and writes the combined JUnit report to destination
+func CombineRerunReport(previousPath, rerunPath, destination string) error {
+	output.PrintLog(fmt.Sprintf("%s Combining rerun results with %s", ui.IconWorld, previousPath))
+
+	var previous reporters.JUnitTestSuites
+	var err error
+	if strings.HasSuffix(previousPath, ".json") {
+		previous, err = junitFromGinkgoReports(previousPath, filepath.Dir(destination))
+	} else {
+		previous, err = LoadJunitReport(previousPath)
+	}
+	if err != nil {
+		return err
+	}
+
+	rerun, err := LoadJunitReport(rerunPath)
+	if err != nil {
+		return err
+	}
+
+	if err = WriteJunitReport(MergeJunitReports(previous, rerun), destination); err != nil {
+		return err
+	}
+
+	output.PrintLog(fmt.Sprintf("%s Combined report written to %s", ui.IconCheckMark, destination))
+	return nil
+}
+
+// junitFromGinkgoReports converts a ginkgo JSON report to JUnit using ginkgo's own JUnit reporter
+func junitFromGinkgoReports(path, tmpDir string) (reporters.JUnitTestSuites, error) {
+	reports, err := LoadGinkgoReports(path)
+	if err != nil {
+		return reporters.JUnitTestSuites{}, err
+	}
+
+	sources := []string{}
+	for i, report := range reports {
+		source := filepath.Join(tmpDir, fmt.Sprintf(".previous-%d.xml", i))
+		if err = reporters.GenerateJUnitReport(report, source); err != nil {
+			return reporters.JUnitTestSuites{}, err
+		}
+		sources = append(sources, source)
+	}
+
+	destination := filepath.Join(tmpDir, ".previous.xml")
+	defer os.Remove(destination)
+	if _, err = reporters.MergeAndCleanupJUnitReports(sources, destination); err != nil {
+		return reporters.JUnitTestSuites{}, err
+	}
+
+	return LoadJunitReport(destination)
+}
+
+func failedSpecTextsFromGinkgoReports(reports []types.Report) []string {
+	texts := []string{}
+	for _, report := range reports {
+		setupFailed := false
+		for _, spec := range report.SpecReports {
+			if spec.LeafNodeType.Is(types.NodeTypesForSuiteLevelNodes) && spec.Failed() {
+				setupFailed = true
+			}
+		}
+
+		for _, spec := range report.SpecReports {
+			if spec.LeafNodeType != types.NodeTypeIt {
+				continue
+			}
+
+			if spec.Failed() || (setupFailed && spec.State == types.SpecStateSkipped) {
+				texts = append(texts, FocusText(report.SuiteDescription, spec.FullText()))
+			}
+		}
+	}
+
+	return texts
+}
+
+func failedSpecTextsFromJunit(report reporters.JUnitTestSuites) []string {
+	texts := []string{}
+	for _, suite := range report.TestSuites {
+		setupFailed := false
+		for _, test := range suite.TestCases {
+			if !strings.HasPrefix(test.Name, "[It]") && (test.Failure != nil || test.Error != nil) {
+				setupFailed = true
+			}
+		}
+
+		for _, test := range suite.TestCases {
+			if !strings.HasPrefix(test.Name, "[It] ") {
+				continue
+			}
+
+			if test.Failure != nil || test.Error != nil || (setupFailed && test.Skipped != nil && test.Skipped.Message != "pending") {
+				texts = append(texts, FocusText(suite.Name, specTextFromJunitName(test.Name)))
+			}
+		}
+	}
+
+	return texts
+}
+
+// specTextFromJunitName strips the node type prefix and the labels suffix from a ginkgo JUnit test case name
+func specTextFromJunitName(name string) string {
+	text := strings.TrimPrefix(name, "[It] ")
+	if strings.HasSuffix(text, "]") {
+		if i := strings.LastIndex(text, " ["); i != -1 {
+			text = text[:i]
+		}
+	}
+
+	return text
+}
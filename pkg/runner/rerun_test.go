@@ -0,0 +1,151 @@
+package runner
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/kubeshop/testkube/pkg/api/v1/testkube"
+	"github.com/kubeshop/testkube/pkg/envs"
+	"github.com/onsi/ginkgo/v2/reporters"
+	"github.com/onsi/ginkgo/v2/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRerun(t *testing.T) {
+	previous := types.Report{
+		SuiteDescription: "API Suite",
+		SpecReports: types.SpecReports{
+			newSpecReport([]string{"API"}, "responds (fast)", types.SpecStateFailed, "smoke"),
+			newSpecReport([]string{"API"}, "responds", types.SpecStatePassed),
+			newSpecReport([]string{"API"}, "times out", types.SpecStateTimedout),
+		},
+	}
+
+	t.Run("FailedSpecTexts should read failed specs from JSON and JUnit reports", func(t *testing.T) {
+		dir := t.TempDir()
+		assert.NoError(t, reporters.GenerateJSONReport(previous, filepath.Join(dir, "report.json")))
+		assert.NoError(t, reporters.GenerateJUnitReport(previous, filepath.Join(dir, "report.xml")))
+
+		texts, err := FailedSpecTexts(filepath.Join(dir, "report.json"))
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"API Suite API responds (fast)", "API Suite API times out"}, texts)
+
+		texts, err = FailedSpecTexts(filepath.Join(dir, "report.xml"))
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"API Suite API responds (fast)", "API Suite API times out"}, texts)
+	})
+
+	t.Run("FailedSpecTexts should include specs skipped because of a failed suite setup", func(t *testing.T) {
+		report := types.Report{
+			SuiteDescription: "API Suite",
+			SpecReports: types.SpecReports{
+				{LeafNodeType: types.NodeTypeBeforeSuite, State: types.SpecStateFailed},
+				newSpecReport([]string{"API"}, "responds", types.SpecStateSkipped),
+			},
+		}
+		assert.Equal(t, []string{"API Suite API responds"}, failedSpecTextsFromGinkgoReports([]types.Report{report}))
+	})
+
+	t.Run("RerunFocus should match exactly the failed specs the way ginkgo matches the focus", func(t *testing.T) {
+		focus := RerunFocus([]string{"API Suite API responds (fast)", "API Suite API times out"})
+		assert.NotContains(t, flagValue(focus), " ")
+
+		// ginkgo matches the focus against the suite description and the spec text
+		re := regexp.MustCompile(flagValue(focus))
+		assert.True(t, re.MatchString("API Suite API responds (fast)"))
+		assert.True(t, re.MatchString("API Suite API times out"))
+		assert.False(t, re.MatchString("API Suite API responds"))
+		assert.False(t, re.MatchString("API Suite API times outright"))
+	})
+
+	t.Run("RerunFocus should not match specs whose text ends with a failed spec text", func(t *testing.T) {
+		report := types.Report{
+			SuiteDescription: "API Suite",
+			SpecReports: types.SpecReports{
+				newSpecReport(nil, "responds", types.SpecStateFailed),
+				newSpecReport([]string{"API"}, "responds", types.SpecStatePassed),
+				newSpecReport([]string{"Other API"}, "responds", types.SpecStatePassed),
+			},
+		}
+		other := types.Report{
+			SuiteDescription: "Other Suite",
+			SpecReports:      types.SpecReports{newSpecReport(nil, "responds", types.SpecStatePassed)},
+		}
+
+		re := regexp.MustCompile(flagValue(RerunFocus(failedSpecTextsFromGinkgoReports([]types.Report{report, other}))))
+		assert.True(t, re.MatchString(FocusText(report.SuiteDescription, "responds")))
+		assert.False(t, re.MatchString(FocusText(report.SuiteDescription, "API responds")))
+		assert.False(t, re.MatchString(FocusText(report.SuiteDescription, "Other API responds")))
+		assert.False(t, re.MatchString(FocusText(other.SuiteDescription, "responds")))
+	})
+
+	t.Run("CombineRerunReport should replace results of specs that ran again", func(t *testing.T) {
+		dir := t.TempDir()
+		rerun := types.Report{
+			SuiteDescription: "API Suite",
+			SpecReports: types.SpecReports{
+				newSpecReport([]string{"API"}, "responds (fast)", types.SpecStatePassed, "smoke"),
+				newSpecReport([]string{"API"}, "responds", types.SpecStateSkipped),
+				newSpecReport([]string{"API"}, "times out", types.SpecStateFailed),
+			},
+		}
+		assert.NoError(t, reporters.GenerateJSONReport(previous, filepath.Join(dir, "previous.json")))
+		assert.NoError(t, reporters.GenerateJUnitReport(rerun, filepath.Join(dir, "report.xml")))
+
+		combinedPath := filepath.Join(dir, "combined-report.xml")
+		assert.NoError(t, CombineRerunReport(filepath.Join(dir, "previous.json"), filepath.Join(dir, "report.xml"), combinedPath))
+
+		combined, err := LoadJunitReport(combinedPath)
+		assert.NoError(t, err)
+		assert.Equal(t, 3, combined.Tests)
+		assert.Equal(t, 1, combined.Failures)
+		assert.Len(t, combined.TestSuites, 1)
+
+		cases := combined.TestSuites[0].TestCases
+		assert.Equal(t, "passed", cases[0].Status)
+		assert.Equal(t, "passed", cases[1].Status)
+		assert.Equal(t, "failed", cases[2].Status)
+		assert.NoFileExists(t, filepath.Join(dir, ".previous.xml"))
+	})
+
+	t.Run("ParseArtifactReport should read the execution and artifact name", func(t *testing.T) {
+		executionID, name, ok, err := ParseArtifactReport("artifacts:64327a1f/reports/report.json")
+		assert.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, "64327a1f", executionID)
+		assert.Equal(t, "reports/report.json", name)
+
+		_, _, ok, err = ParseArtifactReport("reports/report.json")
+		assert.NoError(t, err)
+		assert.False(t, ok)
+
+		_, _, _, err = ParseArtifactReport("artifacts:64327a1f")
+		assert.Error(t, err)
+	})
+
+	t.Run("RerunReportPath should download reports of previous executions with the scraper", func(t *testing.T) {
+		root := t.TempDir()
+		dataDir := t.TempDir()
+		assert.NoError(t, os.MkdirAll(filepath.Join(root, "64327a1f"), 0755))
+		assert.NoError(t, os.WriteFile(filepath.Join(root, "64327a1f", "report.json"), []byte("[]"), 0644))
+
+		r := &GinkgoRunner{Params: envs.Params{DataDir: dataDir}, Scraper: NewFilesystemScraper(root)}
+		path, err := r.RerunReportPath(testkube.Execution{Id: "7a3c9e21"}, "artifacts:64327a1f/report.json", "/repo")
+		assert.NoError(t, err)
+		assert.Equal(t, filepath.Join(dataDir, "rerun", "7a3c9e21", "report.json"), path)
+		assert.FileExists(t, path)
+
+		path, err = r.RerunReportPath(testkube.Execution{Id: "7a3c9e21"}, "reports/report.json", "/repo")
+		assert.NoError(t, err)
+		assert.Equal(t, filepath.Join("/repo", "reports", "report.json"), path)
+
+		_, err = r.RerunReportPath(testkube.Execution{Id: "7a3c9e21"}, "artifacts:64327a1f/missing.json", "/repo")
+		assert.Error(t, err)
+
+		r.Scraper = NoopScraper{}
+		_, err = r.RerunReportPath(testkube.Execution{Id: "7a3c9e21"}, "artifacts:64327a1f/report.json", "/repo")
+		assert.Error(t, err)
+	})
+}
@@ -1,6 +1,7 @@
 package runner
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -9,18 +10,44 @@ import (
 	junit "github.com/joshdk/go-junit"
 	"github.com/kubeshop/testkube/pkg/api/v1/testkube"
 	"github.com/kubeshop/testkube/pkg/envs"
-	"github.com/kubeshop/testkube/pkg/executor"
 	"github.com/kubeshop/testkube/pkg/executor/content"
 	"github.com/kubeshop/testkube/pkg/executor/env"
 	"github.com/kubeshop/testkube/pkg/executor/output"
 	"github.com/kubeshop/testkube/pkg/executor/runner"
 	"github.com/kubeshop/testkube/pkg/executor/scraper"
 	"github.com/kubeshop/testkube/pkg/ui"
+	"github.com/onsi/ginkgo/v2/types"
 )
 
 var ginkgoDefaultParams = InitializeGinkgoParams()
 var ginkgoBin = "ginkgo"
 
+// executorOnlyParams are consumed by the executor itself and never passed to ginkgo
+var executorOnlyParams = map[string]bool{
+	"GinkgoGoPrivate":                true,
+	"GinkgoVersion":                  true,
+	"GinkgoCoverageThreshold":        true,
+	"GinkgoCoveragePackageThreshold": true,
+	"GinkgoFailOnRace":               true,
+	"GinkgoFlakyThreshold":           true,
+	"GinkgoRerunFailedFrom":          true,
+	"GinkgoShardIndex":               true,
+	"GinkgoShardTotal":               true,
+	"GinkgoShardBy":                  true,
+	"GinkgoListLabels":               true,
+	"GinkgoSlowestSpecs":             true,
+	"GinkgoArtifactDirs":             true,
+	"GinkgoArtifactsArchive":         true,
+	"GinkgoArtifactMaxFileSize":      true,
+	"GinkgoArtifactMaxTotalSize":     true,
+	"GinkgoHtmlReport":               true,
+	"GinkgoMarkdownSummary":          true,
+	"GinkgoCtrfReport":               true,
+	"GinkgoTapReport":                true,
+	"GinkgoSarifReport":              true,
+	"GinkgoNormalizeJunit":           true,
+}
+
 func NewGinkgoRunner() (*GinkgoRunner, error) {
 	output.PrintLog(fmt.Sprintf("%s Preparing test runner", ui.IconTruck))
 	params, err := envs.LoadTestkubeVariables()
@@ -28,18 +55,15 @@ func NewGinkgoRunner() (*GinkgoRunner, error) {
 		return nil, fmt.Errorf("could not initialize Ginkgo runner variables: %w", err)
 	}
 
+	artifactsScraper, err := NewScraper(params)
+	if err != nil {
+		return nil, fmt.Errorf("could not initialize Ginkgo runner scraper: %w", err)
+	}
+
 	runner := &GinkgoRunner{
 		Fetcher: content.NewFetcher(""),
-		Scraper: scraper.NewMinioScraper(
-			params.Endpoint,
-			params.AccessKeyID,
-			params.SecretAccessKey,
-			params.Location,
-			params.Token,
-			params.Bucket,
-			params.Ssl,
-		),
-		Params: params,
+		Scraper: artifactsScraper,
+		Params:  params,
 	}
 
 	return runner, nil
@@ -48,6 +72,7 @@ func NewGinkgoRunner() (*GinkgoRunner, error) {
 type GinkgoRunner struct {
 	Params  envs.Params
 	Fetcher content.ContentFetcher
+	// Scraper uploads artifacts, scrapers implementing FileScraper keep their layout
 	Scraper scraper.Scraper
 }
 
@@ -70,6 +95,62 @@ func (r *GinkgoRunner) Run(execution testkube.Execution) (result testkube.Execut
 	// variables of type "secret" will be automatically decoded
 	envManager := env.NewManagerWithVars(execution.Variables)
 	envManager.GetReferenceVars(envManager.Variables)
+
+	// Set up ginkgo params
+	ginkgoParams := FindGinkgoParams(&execution, ginkgoDefaultParams)
+
+	// coverage thresholds need coverage to be collected
+	if (ginkgoParams["GinkgoCoverageThreshold"] != "" || ginkgoParams["GinkgoCoveragePackageThreshold"] != "") && CoverProfileName(ginkgoParams) == "" {
+		ginkgoParams["GinkgoCover"] = "--cover"
+	}
+
+	// dry runs are serial only and are listed from the JSON report
+	if ginkgoParams["GinkgoDryRun"] != "" {
+		delete(ginkgoParams, "GinkgoParallel")
+		delete(ginkgoParams, "GinkgoParallelProcs")
+		if ginkgoParams["GinkgoJsonReport"] == "" {
+			ginkgoParams["GinkgoJsonReport"] = ginkgoDefaultParams["GinkgoJsonReport"]
+		}
+	}
+
+	// check the toolchain before fetching so all environment problems are reported at once
+	if err = r.Diagnose(ginkgoParams).Err(); err != nil {
+		return result, err
+	}
+
+	// thresholds, summaries and upload limits are applied after the run, invalid values shouldn't wait for it
+	options, err := ParseExecutorOptions(ginkgoParams)
+	if err != nil {
+		output.PrintLog(fmt.Sprintf("%s %s", ui.IconCross, err.Error()))
+		return result, err
+	}
+
+	// set up artifacts directory exported to the specs
+	artifactsPath, err := PrepareArtifactsDir(r.Params.DataDir, execution.Id)
+	if err != nil {
+		output.PrintLog(fmt.Sprintf("%s could not set up artifacts directory: %s", ui.IconCross, err.Error()))
+		return result, err
+	}
+
+	// scrape artifacts even if the execution fails on the way, scraping errors are added to the primary error
+	var runPath, reportsPath string
+	defer func() {
+		scrapeErr := r.ScrapeArtifacts(execution, ginkgoParams, options.Upload, runPath, reportsPath, artifactsPath)
+		if scrapeErr == nil {
+			return
+		}
+
+		scrapeErr = fmt.Errorf("scrape artifacts error: %w", scrapeErr)
+		switch {
+		case err != nil:
+			err = fmt.Errorf("%w; %s", err, scrapeErr.Error())
+		case result.ErrorMessage != "":
+			result.ErrorMessage += "; " + scrapeErr.Error()
+		default:
+			result.Err(scrapeErr)
+		}
+	}()
+
 	path, err := r.Fetcher.Fetch(execution.Content)
 	if err != nil {
 		return result, err
@@ -85,14 +166,124 @@ func (r *GinkgoRunner) Run(execution testkube.Execution) (result testkube.Execut
 		return result, fmt.Errorf("passing ginkgo test as single file not implemented yet")
 	}
 
-	// Set up ginkgo params
-	ginkgoParams := FindGinkgoParams(&execution, ginkgoDefaultParams)
-
-	runPath := path
+	runPath = path
+	repoPath := path
 	if execution.Content.Repository != nil && execution.Content.Repository.WorkingDir != "" {
+		repoPath = filepath.Join(r.Params.DataDir, "repo")
 		runPath = filepath.Join(r.Params.DataDir, "repo", execution.Content.Repository.WorkingDir)
 		path = filepath.Join(r.Params.DataDir, "repo", execution.Content.Repository.Path)
 	}
+	reportsPath = filepath.Join(path, "reports")
+
+	// pick the ginkgo CLI matching the Ginkgo version of the suite
+	suitePath := runPath
+	if ginkgoParams["GinkgoTestPackage"] != "" {
+		suitePath = filepath.Join(path, ginkgoParams["GinkgoTestPackage"])
+	}
+	if err = CheckGinkgoV1(suitePath); err != nil {
+		return result, err
+	}
+	bin := ResolveGinkgoBin(ginkgoParams["GinkgoVersion"], r.Params.DataDir, suitePath, repoPath)
+	ginkgoCheck := Diagnostics{CheckGinkgoVersion(bin, suitePath, repoPath)}
+	ginkgoCheck.Print()
+	if err = ginkgoCheck.Err(); err != nil {
+		return result, err
+	}
+
+	// configure access to private Go modules with the same credentials as the checkout
+	goPrivateUsername, goPrivateToken := GoPrivateCredentials(execution, r.Params)
+	HideGoPrivateVariables(&execution)
+	if goPrivateUsername != "" || goPrivateToken != "" {
+		patterns := GoPrivatePatterns(ginkgoParams["GinkgoGoPrivate"], execution.Content.Repository.Uri)
+		if len(patterns) > 0 {
+			goPrivate, err := PrepareGoPrivate(r.Params.DataDir, patterns, goPrivateUsername, goPrivateToken)
+			if err != nil {
+				output.PrintLog(fmt.Sprintf("%s could not configure private Go modules: %s", ui.IconCross, err.Error()))
+				return result, err
+			}
+
+			defer func() {
+				if cleanupErr := goPrivate.Cleanup(); cleanupErr != nil {
+					output.PrintLog(fmt.Sprintf("%s could not clean up private Go modules config: %s", ui.IconCross, cleanupErr.Error()))
+				}
+			}()
+		}
+	}
+
+	// rerun only specs that failed in a previous report
+	rerunFrom := ginkgoParams["GinkgoRerunFailedFrom"]
+	if rerunFrom != "" {
+		if rerunFrom, err = r.RerunReportPath(execution, rerunFrom, path); err != nil {
+			output.PrintLog(fmt.Sprintf("%s could not get previous report: %s", ui.IconCross, err.Error()))
+			return result, err
+		}
+
+		failed, err := FailedSpecTexts(rerunFrom)
+		if err != nil {
+			output.PrintLog(fmt.Sprintf("%s could not read failed specs from previous report: %s", ui.IconCross, err.Error()))
+			return result, err
+		}
+
+		if len(failed) == 0 {
+			output.PrintLog(fmt.Sprintf("%s No failed specs to rerun in %s", ui.IconCheckMark, rerunFrom))
+			result.Output = fmt.Sprintf("No failed specs to rerun in %s\n", ginkgoParams["GinkgoRerunFailedFrom"])
+			result.OutputType = "text/plain"
+			result.Success()
+			return result, nil
+		}
+
+		output.PrintLog(fmt.Sprintf("%s Rerunning %d failed specs from %s", ui.IconWorld, len(failed), rerunFrom))
+		ginkgoParams["GinkgoFocusFilter"] = RerunFocus(failed)
+	}
+
+	// run only the packages or specs assigned to this shard
+	if ginkgoParams["GinkgoShardTotal"] != "" {
+		shard, err := ParseShard(ginkgoParams["GinkgoShardIndex"], ginkgoParams["GinkgoShardTotal"])
+		if err != nil {
+			return result, err
+		}
+
+		var selected []string
+		if ginkgoParams["GinkgoShardBy"] == shardBySpec {
+			reports, err := ListSpecs(bin, path, runPath, ginkgoParams)
+			if err != nil {
+				output.PrintLog(fmt.Sprintf("%s could not list specs for sharding: %s", ui.IconCross, err.Error()))
+				return result, err
+			}
+
+			selected = shard.Select(SpecsToRun(reports))
+			if len(selected) > 0 {
+				ginkgoParams["GinkgoFocusFilter"] = RerunFocus(selected)
+			}
+		} else {
+			base := runPath
+			if path != runPath {
+				base = path
+			}
+
+			packages, err := FindSuitePackages(suitePath, base)
+			if err != nil {
+				output.PrintLog(fmt.Sprintf("%s could not find suite packages for sharding: %s", ui.IconCross, err.Error()))
+				return result, err
+			}
+
+			selected = shard.Select(packages)
+			if len(selected) > 0 {
+				ginkgoParams["GinkgoTestPackage"] = strings.Join(selected, " ")
+				delete(ginkgoParams, "GinkgoRecursive")
+			}
+		}
+
+		if len(selected) == 0 {
+			output.PrintLog(fmt.Sprintf("%s Nothing to run in shard %s", ui.IconCheckMark, shard))
+			result.Output = fmt.Sprintf("Nothing to run in shard %s\n", shard)
+			result.OutputType = "text/plain"
+			result.Success()
+			return result, nil
+		}
+
+		output.PrintLog(fmt.Sprintf("%s Running shard %s: %s", ui.IconWorld, shard, strings.Join(selected, ", ")))
+	}
 
 	// Set up ginkgo potential args
 	ginkgoArgs, err := BuildGinkgoArgs(ginkgoParams, path, runPath)
@@ -103,7 +294,6 @@ func (r *GinkgoRunner) Run(execution testkube.Execution) (result testkube.Execut
 	ginkgoArgsAndFlags := append(ginkgoArgs, ginkgoPassThroughFlags...)
 
 	// set up reports directory
-	reportsPath := filepath.Join(path, "reports")
 	if _, err := os.Stat(reportsPath); os.IsNotExist(err) {
 		mkdirErr := os.Mkdir(reportsPath, os.ModePerm)
 		if mkdirErr != nil {
@@ -113,48 +303,241 @@ func (r *GinkgoRunner) Run(execution testkube.Execution) (result testkube.Execut
 	}
 
 	// run executor here
-	out, err := executor.Run(runPath, ginkgoBin, envManager, ginkgoArgsAndFlags...)
+	out, err := RunGinkgo(runPath, bin, envManager, ginkgoArgsAndFlags...)
 	out = envManager.ObfuscateSecrets(out)
 
-	// generate report/result
-	if ginkgoParams["GinkgoJsonReport"] != "" {
-		moveErr := MoveReport(runPath, reportsPath, strings.Split(ginkgoParams["GinkgoJsonReport"], " ")[1])
-		if moveErr != nil {
-			output.PrintLog(fmt.Sprintf("%s could not move JSON report: %s", ui.IconCross, moveErr.Error()))
-			return result, moveErr
+	// generate report/result from whatever reports exist, failing to move them doesn't hide the ginkgo error
+	if moveErr := MoveReports(ginkgoParams, runPath, reportsPath); moveErr != nil {
+		if err != nil {
+			err = fmt.Errorf("%w; %s", err, moveErr.Error())
+		} else {
+			err = moveErr
 		}
 	}
-	if ginkgoParams["GinkgoJunitReport"] != "" {
-		moveErr := MoveReport(runPath, reportsPath, strings.Split(ginkgoParams["GinkgoJunitReport"], " ")[1])
-		if moveErr != nil {
-			output.PrintLog(fmt.Sprintf("%s could not move Junit report: %s", ui.IconCross, moveErr.Error()))
-			return result, moveErr
+
+	junitReportPath := filepath.Join(reportsPath, flagValue(ginkgoParams["GinkgoJunitReport"]))
+	if rerunFrom != "" {
+		combinedReportPath := filepath.Join(reportsPath, "combined-"+filepath.Base(junitReportPath))
+		if combineErr := CombineRerunReport(rerunFrom, junitReportPath, combinedReportPath); combineErr != nil {
+			output.PrintLog(fmt.Sprintf("%s could not combine rerun results: %s", ui.IconCross, combineErr.Error()))
+		} else {
+			junitReportPath = combinedReportPath
 		}
 	}
-	if ginkgoParams["GinkgoTeamCityReport"] != "" {
-		moveErr := MoveReport(runPath, reportsPath, strings.Split(ginkgoParams["GinkgoTeamCityReport"], " ")[1])
-		if moveErr != nil {
-			output.PrintLog(fmt.Sprintf("%s could not move TeamCity report: %s", ui.IconCross, moveErr.Error()))
-			return result, moveErr
+
+	// JSON report has details missing in JUnit, e.g. spec attempts and labels
+	var reports []types.Report
+	if ginkgoParams["GinkgoJsonReport"] != "" {
+		var jerr error
+		reports, jerr = LoadGinkgoReports(filepath.Join(reportsPath, flagValue(ginkgoParams["GinkgoJsonReport"])))
+		if jerr != nil {
+			output.PrintLog(fmt.Sprintf("%s could not load JSON report: %s", ui.IconCross, jerr.Error()))
+		}
+	}
+
+	// rewrite the scraped JUnit report for CI parsers, steps are still mapped from the report written by ginkgo,
+	// which is kept in the data directory so it isn't scraped next to the normalized one
+	if ginkgoParams["GinkgoNormalizeJunit"] == "true" && ginkgoParams["GinkgoDryRun"] == "" {
+		originalDir := filepath.Join(r.Params.DataDir, "junit", execution.Id)
+		originalReportPath, normalizeErr := NormalizeJunitReportFile(junitReportPath, originalDir, reports, runPath)
+		if normalizeErr != nil {
+			output.PrintLog(fmt.Sprintf("%s could not normalize JUnit report: %s", ui.IconWarning, normalizeErr.Error()))
+		} else {
+			output.PrintLog(fmt.Sprintf("%s Normalized JUnit report %s", ui.IconCheckMark, junitReportPath))
+			junitReportPath = originalReportPath
 		}
 	}
-	suites, serr := junit.IngestFile(filepath.Join(reportsPath, strings.Split(ginkgoParams["GinkgoJunitReport"], " ")[1]))
+
+	suites, serr := junit.IngestFile(junitReportPath)
 	result = MapJunitToExecutionResults(out, suites)
 	output.PrintLog(fmt.Sprintf("%s Mapped Junit to Execution Results...", ui.IconCheckMark))
 
-	// scrape artifacts first even if there are errors above
+	if ginkgoParams["GinkgoDryRun"] != "" {
+		result = MapDryRunToExecutionResults(out, reports, runPath)
+		output.PrintLog(fmt.Sprintf("%s Mapped dry run to Execution Results...", ui.IconCheckMark))
+	}
+
+	// surface failed suite level nodes, they are the root cause of all specs being skipped
+	var suiteErr error
+	if len(reports) > 0 {
+		failures := FindSuiteFailures(reports)
+		AttachSuiteFailures(&result, failures)
+		suiteErr = SuiteFailuresError(failures)
+	}
 
-	if r.Params.ScrapperEnabled {
-		directories := []string{
-			reportsPath,
+	// keep labels of specs and break results down by label
+	if len(reports) > 0 && ginkgoParams["GinkgoDryRun"] == "" {
+		AddSpecLabels(&result, reports)
+		result.Output += "\n" + LabelResultsSummary(ResultsByLabel(reports))
+	}
+	if ginkgoParams["GinkgoListLabels"] == "true" {
+		labels, labelsErr := ListLabels(bin, path, runPath, ginkgoParams)
+		if labelsErr != nil {
+			output.PrintLog(fmt.Sprintf("%s %s", ui.IconCross, labelsErr.Error()))
+		} else {
+			result.Output += "\nLabels in suites:\n" + labels
+		}
+	}
+
+	// record timeline of specs and summarise the slowest ones
+	var timings []SpecTiming
+	if len(reports) > 0 && ginkgoParams["GinkgoDryRun"] == "" {
+		timings = SpecTimings(reports)
+		if werr := WriteSpecTimings(timings, filepath.Join(reportsPath, specTimingsReport)); werr != nil {
+			output.PrintLog(fmt.Sprintf("%s could not write spec timings: %s", ui.IconWarning, werr.Error()))
+		}
+		if options.SlowestSpecs > 0 {
+			slowest := SlowestSpecs(timings, options.SlowestSpecs)
+			if werr := WriteSpecTimings(slowest, filepath.Join(reportsPath, slowestSpecsReport)); werr != nil {
+				output.PrintLog(fmt.Sprintf("%s could not write slowest specs: %s", ui.IconWarning, werr.Error()))
+			}
+			result.Output += "\n" + SlowestSpecsSummary(slowest)
+		}
+	}
+
+	// attach progress reports of slow or hung specs
+	if len(reports) > 0 {
+		progress := FindSpecProgress(reports)
+		if len(progress) > 0 {
+			AttachProgressReports(&result, progress)
+			result.Output += "\n" + ProgressReportsSummary(progress)
+		}
+	}
+
+	// detect specs that passed only after retries
+	flaky := FindFlakySpecs(reports)
+	if len(flaky) > 0 {
+		MarkFlakySpecs(&result, flaky)
+		result.Output += "\n" + FlakySpecsSummary(flaky)
+	}
+	flakyErr := CheckFlakyThreshold(flaky, options.FlakyThreshold)
+
+	// render a readable report next to the machine readable ones
+	if ginkgoParams["GinkgoHtmlReport"] != "" && len(reports) > 0 && ginkgoParams["GinkgoDryRun"] == "" {
+		if herr := WriteHTMLReport(reports, filepath.Join(reportsPath, ginkgoParams["GinkgoHtmlReport"])); herr != nil {
+			output.PrintLog(fmt.Sprintf("%s could not write HTML report: %s", ui.IconWarning, herr.Error()))
+		}
+	}
+	if ginkgoParams["GinkgoCtrfReport"] != "" && len(reports) > 0 && ginkgoParams["GinkgoDryRun"] == "" {
+		if cerr := WriteCtrfReport(reports, filepath.Join(reportsPath, ginkgoParams["GinkgoCtrfReport"])); cerr != nil {
+			output.PrintLog(fmt.Sprintf("%s could not write CTRF report: %s", ui.IconWarning, cerr.Error()))
+		}
+	}
+	if ginkgoParams["GinkgoTapReport"] != "" && len(reports) > 0 && ginkgoParams["GinkgoDryRun"] == "" {
+		if terr := WriteTAPReport(reports, filepath.Join(reportsPath, ginkgoParams["GinkgoTapReport"])); terr != nil {
+			output.PrintLog(fmt.Sprintf("%s could not write TAP report: %s", ui.IconWarning, terr.Error()))
+		}
+	}
+	if ginkgoParams["GinkgoSarifReport"] != "" && len(reports) > 0 && ginkgoParams["GinkgoDryRun"] == "" {
+		if sarifErr := WriteSarifReport(reports, repoPath, filepath.Join(reportsPath, ginkgoParams["GinkgoSarifReport"])); sarifErr != nil {
+			output.PrintLog(fmt.Sprintf("%s could not write SARIF report: %s", ui.IconWarning, sarifErr.Error()))
 		}
-		err := r.Scraper.Scrape(execution.Id, directories)
+	}
+
+	// extract data race reports and attach them to the specs they occurred in
+	races := ParseDataRaces(string(out))
+	var raceErr error
+	if len(races) > 0 {
+		AttachDataRaces(&result, suites)
+		result.Output += "\n" + DataRacesSummary(races)
+		if ginkgoParams["GinkgoFailOnRace"] == "true" {
+			raceErr = CheckDataRaces(races)
+		}
+	}
+
+	// merge cover profiles into reports and add coverage summary to the result
+	var coverage *CoverageSummary
+	if profileName := CoverProfileName(ginkgoParams); profileName != "" {
+		var coverErr error
+		coverage, coverErr = CollectCoverage(runPath, reportsPath, profileName)
+		if coverErr != nil {
+			output.PrintLog(fmt.Sprintf("%s could not collect coverage: %s", ui.IconCross, coverErr.Error()))
+		} else {
+			result.Output += "\n" + coverage.String()
+		}
+	}
+	coverageErr := CheckCoverageThreshold(coverage, options.CoverageThreshold, options.CoveragePackageThreshold)
+
+	// all problems are reported, e.g. failed specs don't hide packages below the coverage threshold
+	result.WithErrors(combineErrors(suiteErr, err, serr, coverageErr, raceErr, flakyErr))
+
+	// summarise results for PR comments
+	if ginkgoParams["GinkgoMarkdownSummary"] != "" {
+		summary := RenderMarkdownSummary(result, reports, SlowestSpecs(timings, options.SlowestSpecs), coverage)
+		if merr := WriteMarkdownSummary(summary, filepath.Join(reportsPath, ginkgoParams["GinkgoMarkdownSummary"])); merr != nil {
+			output.PrintLog(fmt.Sprintf("%s could not write Markdown summary: %s", ui.IconWarning, merr.Error()))
+		}
+	}
+
+	return result, nil
+}
+
+// ScrapeArtifacts uploads reports, the artifacts directory and artifacts requested by the test, paths
+// not set up yet are skipped
+func (r *GinkgoRunner) ScrapeArtifacts(execution testkube.Execution, ginkgoParams map[string]string, uploadOptions UploadOptions,
+	runPath, reportsPath, artifactsPath string) error {
+	if !r.Params.ScrapperEnabled {
+		return nil
+	}
+
+	sources := []ArtifactSource{{Path: artifactsPath, Name: specArtifactsName}}
+	if reportsPath != "" {
+		if err := os.MkdirAll(reportsPath, os.ModePerm); err != nil {
+			return err
+		}
+		sources = append([]ArtifactSource{{Path: reportsPath, Name: reportsArtifactsName}}, sources...)
+	}
+
+	if runPath != "" {
+		artifacts, err := ArtifactPaths(runPath, reportsPath, ArtifactPatterns(execution, ginkgoParams["GinkgoArtifactDirs"]))
 		if err != nil {
-			return *result.WithErrors(fmt.Errorf("scrape artifacts error: %w", err)), nil
+			return err
+		}
+		sources = append(sources, artifacts...)
+	}
+
+	files, err := PrepareUpload(sources, reportsPath, filepath.Join(r.Params.DataDir, "upload", execution.Id), uploadOptions)
+	if err != nil {
+		return err
+	}
+
+	if fileScraper, ok := r.Scraper.(FileScraper); ok {
+		return fileScraper.ScrapeFiles(execution.Id, files)
+	}
+
+	// other scrapers are given a directory with the files laid out under their names
+	scrapePath := filepath.Join(r.Params.DataDir, "scrape", execution.Id)
+	if err = stageFiles(files, scrapePath); err != nil {
+		return err
+	}
+
+	return r.Scraper.Scrape(execution.Id, []string{scrapePath})
+}
+
+// MoveReports moves JSON, JUnit and TeamCity reports written by ginkgo to reportsPath. Every report is moved
+// even if another one is missing, so all of them are scraped, errors are combined.
+func MoveReports(ginkgoParams map[string]string, runPath, reportsPath string) error {
+	moveErrs := []string{}
+	for _, report := range []struct{ param, name string }{
+		{"GinkgoJsonReport", "JSON"},
+		{"GinkgoJunitReport", "Junit"},
+		{"GinkgoTeamCityReport", "TeamCity"},
+	} {
+		if ginkgoParams[report.param] == "" {
+			continue
+		}
+
+		if err := MoveReport(runPath, reportsPath, flagValue(ginkgoParams[report.param])); err != nil {
+			output.PrintLog(fmt.Sprintf("%s could not move %s report: %s", ui.IconCross, report.name, err.Error()))
+			moveErrs = append(moveErrs, fmt.Sprintf("could not move %s report: %s", report.name, err.Error()))
 		}
 	}
 
-	return *result.WithErrors(err, serr), nil
+	if len(moveErrs) > 0 {
+		return errors.New(strings.Join(moveErrs, "; "))
+	}
+
+	return nil
 }
 
 func MoveReport(path string, reportsPath string, reportFileName string) error {
@@ -185,17 +568,42 @@ func InitializeGinkgoParams() map[string]string {
 	ginkgoParams["GinkgoRepeat"] = ""                               // --repeat N
 	ginkgoParams["GinkgoFlakeAttempts"] = ""                        // --flake-attempts N
 	ginkgoParams["GinkgoTimeout"] = ""                              // --timeout=duration
+	ginkgoParams["GinkgoPollProgressAfter"] = ""                    // --poll-progress-after=duration
+	ginkgoParams["GinkgoPollProgressInterval"] = ""                 // --poll-progress-interval=duration
 	ginkgoParams["GinkgoSkipPackage"] = ""                          // --skip-package list,of,packages
 	ginkgoParams["GinkgoFailFast"] = ""                             // --fail-fast
 	ginkgoParams["GinkgoKeepGoing"] = "--keep-going"                // --keep-going
 	ginkgoParams["GinkgoFailOnPending"] = ""                        // --fail-on-pending
+	ginkgoParams["GinkgoDryRun"] = ""                               // --dry-run [lists specs selected by the filters without running them]
 	ginkgoParams["GinkgoCover"] = ""                                // --cover
 	ginkgoParams["GinkgoCoverProfile"] = ""                         // --coverprofile cover.profile
 	ginkgoParams["GinkgoRace"] = ""                                 // --race
 	ginkgoParams["GinkgoTrace"] = "--trace"                         // --trace
-	ginkgoParams["GinkgoJsonReport"] = ""                           // --json-report report.json [will be stored in reports/filename]
+	ginkgoParams["GinkgoJsonReport"] = "--json-report report.json"  // --json-report report.json [will be stored in reports/filename, needed for flaky specs detection]
 	ginkgoParams["GinkgoJunitReport"] = "--junit-report report.xml" // --junit-report report.xml [will be stored in reports/filename]
 	ginkgoParams["GinkgoTeamCityReport"] = ""                       // --teamcity-report report.teamcity [will be stored in reports/filename]
+	ginkgoParams["GinkgoGoPrivate"] = ""                            // list,of,module/patterns [executor only, defaults to the repository organisation]
+	ginkgoParams["GinkgoVersion"] = "auto"                          // auto|bundled|v2.x.y [executor only, auto matches the go.mod of the suite]
+	ginkgoParams["GinkgoCoverageThreshold"] = ""                    // 80 [executor only, minimal total statement coverage in percent]
+	ginkgoParams["GinkgoCoveragePackageThreshold"] = ""             // 60 [executor only, minimal statement coverage of every package in percent]
+	ginkgoParams["GinkgoFailOnRace"] = ""                           // true [executor only, fails the execution on detected data races]
+	ginkgoParams["GinkgoFlakyThreshold"] = ""                       // N [executor only, fails the execution when more than N specs passed only after retries]
+	ginkgoParams["GinkgoRerunFailedFrom"] = ""                      // path/to/report.json|report.xml [executor only, reruns failed specs of a previous report]
+	ginkgoParams["GinkgoShardIndex"] = ""                           // 0..N-1 [executor only, zero based index of the shard to run]
+	ginkgoParams["GinkgoShardTotal"] = ""                           // N [executor only, number of shards the suite is split into]
+	ginkgoParams["GinkgoShardBy"] = ""                              // package|spec [executor only, defaults to package]
+	ginkgoParams["GinkgoListLabels"] = ""                           // true [executor only, lists all labels of the suites with ginkgo labels]
+	ginkgoParams["GinkgoSlowestSpecs"] = "10"                       // N [executor only, number of slowest specs summarised in the output]
+	ginkgoParams["GinkgoArtifactDirs"] = ""                         // dir,path/*.png [executor only, directories and glob patterns scraped along with reports]
+	ginkgoParams["GinkgoArtifactsArchive"] = ""                     // true [executor only, uploads reports and artifacts as a single artifacts.tar.gz]
+	ginkgoParams["GinkgoArtifactMaxFileSize"] = ""                  // 10MB [executor only, larger files are not uploaded]
+	ginkgoParams["GinkgoArtifactMaxTotalSize"] = ""                 // 100MB [executor only, files over the total size are not uploaded]
+	ginkgoParams["GinkgoHtmlReport"] = "report.html"                // report.html [executor only, will be stored in reports/filename, rendered from the JSON report]
+	ginkgoParams["GinkgoMarkdownSummary"] = ""                      // summary.md [executor only, will be stored in reports/filename, ready to be posted as a PR comment]
+	ginkgoParams["GinkgoCtrfReport"] = ""                           // ctrf-report.json [executor only, will be stored in reports/filename, Common Test Report Format]
+	ginkgoParams["GinkgoTapReport"] = ""                            // report.tap [executor only, will be stored in reports/filename, TAP version 13 stream]
+	ginkgoParams["GinkgoSarifReport"] = ""                          // report.sarif [executor only, will be stored in reports/filename, failures annotated at their location]
+	ginkgoParams["GinkgoNormalizeJunit"] = ""                       // true [executor only, rewrites the JUnit report with classnames, It texts and labels as properties]
 
 	output.PrintLog(fmt.Sprintf("%s Initial Ginkgo parameters prepared: %s", ui.IconCheckMark, ginkgoParams))
 	return ginkgoParams
@@ -230,16 +638,18 @@ func BuildGinkgoArgs(params map[string]string, path, runPath string) ([]string,
 		if p == "" {
 			continue
 		}
-		if k != "GinkgoTestPackage" {
+		if k != "GinkgoTestPackage" && !executorOnlyParams[k] {
 			args = append(args, strings.Split(p, " ")...)
 		}
 	}
 
 	if params["GinkgoTestPackage"] != "" {
-		if path != runPath {
-			args = append(args, filepath.Join(path, params["GinkgoTestPackage"]))
-		} else {
-			args = append(args, params["GinkgoTestPackage"])
+		for _, pkg := range strings.Split(params["GinkgoTestPackage"], " ") {
+			if path != runPath {
+				args = append(args, filepath.Join(path, pkg))
+			} else {
+				args = append(args, pkg)
+			}
 		}
 	} else {
 		if path != runPath {
@@ -308,7 +718,7 @@ func MapJunitToExecutionResults(out []byte, suites []junit.Suite) (result testku
 			result.Steps = append(
 				result.Steps,
 				testkube.ExecutionStepResult{
-					Name:     fmt.Sprintf("%s - %s", suite.Name, test.Name),
+					Name:     StepName(suite.Name, test.Name),
 					Duration: test.Duration.String(),
 					Status:   MapStatus(test.Status),
 				})
@@ -328,6 +738,22 @@ func MapJunitToExecutionResults(out []byte, suites []junit.Suite) (result testku
 	return result
 }
 
+// StepName returns the name of the execution step of a JUnit test case
+func StepName(suiteName, testName string) string {
+	return fmt.Sprintf("%s - %s", suiteName, testName)
+}
+
+// FindStep returns the execution step with the given name or nil
+func FindStep(result *testkube.ExecutionResult, name string) *testkube.ExecutionStepResult {
+	for i := range result.Steps {
+		if result.Steps[i].Name == name {
+			return &result.Steps[i]
+		}
+	}
+
+	return nil
+}
+
 func MapStatus(in junit.Status) (out string) {
 	switch string(in) {
 	case "passed":
@@ -341,3 +767,32 @@ func MapStatus(in junit.Status) (out string) {
 func (r *GinkgoRunner) GetType() runner.Type {
 	return runner.TypeMain
 }
+
+// RerunReportPath returns the local path of the GinkgoRerunFailedFrom report. Reports referenced as
+// artifacts:<execution id>/<artifact name> are downloaded with the scraper, paths are relative to runPath.
+func (r *GinkgoRunner) RerunReportPath(execution testkube.Execution, value, runPath string) (string, error) {
+	executionID, name, ok, err := ParseArtifactReport(value)
+	if err != nil {
+		return "", err
+	}
+
+	if !ok {
+		if !filepath.IsAbs(value) {
+			return filepath.Join(runPath, value), nil
+		}
+		return value, nil
+	}
+
+	fetcher, ok := r.Scraper.(ArtifactFetcher)
+	if !ok {
+		return "", fmt.Errorf("the configured scraper can't download artifacts of previous executions")
+	}
+
+	destination := filepath.Join(r.Params.DataDir, "rerun", execution.Id, filepath.Base(filepath.FromSlash(name)))
+	output.PrintLog(fmt.Sprintf("%s Downloading %s of execution %s", ui.IconWorld, name, executionID))
+	if err = fetcher.FetchFile(executionID, name, destination); err != nil {
+		return "", err
+	}
+
+	return destination, nil
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"testing"
 
 	"github.com/kubeshop/testkube/pkg/api/v1/testkube"
@@ -132,6 +133,13 @@ func TestRun(t *testing.T) {
 		assert.Contains(t, argSlice, "report.xml")
 	})
 
+	t.Run("BuildGinkgoArgs should pass space separated test packages as separate args", func(t *testing.T) {
+		params := map[string]string{"GinkgoTestPackage": "e2e other"}
+		argSlice, err := BuildGinkgoArgs(params, "/data/repo/tests", "/data/repo")
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"/data/repo/tests/e2e", "/data/repo/tests/other"}, argSlice)
+	})
+
 	t.Run("BuildGinkgoPassThroughFlags should build pass through flags slice from leftover Variables and from Args", func(t *testing.T) {
 		variables := make(map[string]testkube.Variable)
 		variable_one := testkube.Variable{
@@ -163,4 +171,21 @@ func TestRun(t *testing.T) {
 		assert.Contains(t, passThroughs, "--three")
 		assert.Contains(t, passThroughs, "--four=four")
 	})
+	t.Run("MoveReports should move all existing reports and combine errors of missing ones", func(t *testing.T) {
+		runPath := t.TempDir()
+		reportsPath := filepath.Join(runPath, "reports")
+		assert.NoError(t, os.Mkdir(reportsPath, 0755))
+		assert.NoError(t, os.WriteFile(filepath.Join(runPath, "report.xml"), []byte("junit"), 0644))
+		assert.NoError(t, os.WriteFile(filepath.Join(runPath, "report.teamcity"), []byte("teamcity"), 0644))
+
+		err := MoveReports(map[string]string{
+			"GinkgoJsonReport":     "--json-report report.json",
+			"GinkgoJunitReport":    "--junit-report=report.xml",
+			"GinkgoTeamCityReport": "--teamcity-report report.teamcity",
+		}, runPath, reportsPath)
+		assert.ErrorContains(t, err, "could not move JSON report")
+		assert.NotContains(t, err.Error(), "Junit")
+		assert.FileExists(t, filepath.Join(reportsPath, "report.xml"))
+		assert.FileExists(t, filepath.Join(reportsPath, "report.teamcity"))
+	})
 }
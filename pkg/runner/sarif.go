@@ -0,0 +1,152 @@
+package runner
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/onsi/ginkgo/v2/types"
+)
+
+const (
+	sarifSchema         = "https://json.schemastore.org/sarif-2.1.0.json"
+	sarifVersion        = "2.1.0"
+	sarifSpecFailure    = "ginkgo/spec-failure"
+	sarifSuiteFailure   = "ginkgo/suite-failure"
+	ginkgoDocumentation = "https://onsi.github.io/ginkgo/"
+)
+
+// SarifLog is a SARIF 2.1.0 log with a single run
+type SarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []SarifRun `json:"runs"`
+}
+
+// SarifRun is a single run of a tool
+type SarifRun struct {
+	Tool    SarifTool     `json:"tool"`
+	Results []SarifResult `json:"results"`
+}
+
+// SarifTool describes the tool and its rules
+type SarifTool struct {
+	Driver SarifDriver `json:"driver"`
+}
+
+// SarifDriver is the tool component which produced the results
+type SarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []SarifRule `json:"rules"`
+}
+
+// SarifRule is a kind of result
+type SarifRule struct {
+	ID               string       `json:"id"`
+	ShortDescription SarifMessage `json:"shortDescription"`
+}
+
+// SarifMessage is a plain text message
+type SarifMessage struct {
+	Text string `json:"text"`
+}
+
+// SarifResult is a single failure annotated at its location
+type SarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   SarifMessage    `json:"message"`
+	Locations []SarifLocation `json:"locations,omitempty"`
+}
+
+// SarifLocation is the location of a result
+type SarifLocation struct {
+	PhysicalLocation SarifPhysicalLocation `json:"physicalLocation"`
+}
+
+// SarifPhysicalLocation is a file region, uri is relative to the repository root when possible
+type SarifPhysicalLocation struct {
+	ArtifactLocation SarifArtifactLocation `json:"artifactLocation"`
+	Region           SarifRegion           `json:"region"`
+}
+
+// SarifArtifactLocation is a file
+type SarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+// SarifRegion is a line in a file
+type SarifRegion struct {
+	StartLine int `json:"startLine"`
+}
+
+// NewSarifLog converts failed specs and suite level nodes to SARIF results located at the failure,
+// file paths are made relative to repoPath so code scanning tools can annotate them
+func NewSarifLog(reports []types.Report, repoPath string) SarifLog {
+	run := SarifRun{
+		Tool: SarifTool{Driver: SarifDriver{
+			Name:           "ginkgo",
+			InformationURI: ginkgoDocumentation,
+			Rules: []SarifRule{
+				{ID: sarifSpecFailure, ShortDescription: SarifMessage{Text: "Ginkgo spec failed"}},
+				{ID: sarifSuiteFailure, ShortDescription: SarifMessage{Text: "Ginkgo suite level node failed"}},
+			},
+		}},
+		Results: []SarifResult{},
+	}
+
+	for _, report := range reports {
+		for _, spec := range report.SpecReports {
+			if !spec.Failed() {
+				continue
+			}
+
+			result := SarifResult{
+				RuleID:  sarifSpecFailure,
+				Level:   "error",
+				Message: SarifMessage{Text: SpecStepName(report, spec) + "\n" + strings.TrimSpace(spec.Failure.Message)},
+			}
+			if spec.LeafNodeType.Is(types.NodeTypesForSuiteLevelNodes) {
+				result.RuleID = sarifSuiteFailure
+			}
+
+			location := spec.Failure.Location
+			if location.FileName == "" {
+				location = spec.LeafNodeLocation
+			}
+			if location.FileName != "" {
+				result.Locations = []SarifLocation{{PhysicalLocation: SarifPhysicalLocation{
+					ArtifactLocation: SarifArtifactLocation{URI: sarifURI(location.FileName, repoPath)},
+					Region:           SarifRegion{StartLine: location.LineNumber},
+				}}}
+			}
+
+			run.Results = append(run.Results, result)
+		}
+	}
+
+	return SarifLog{Schema: sarifSchema, Version: sarifVersion, Runs: []SarifRun{run}}
+}
+
+// WriteSarifReport writes the SARIF log of the ginkgo reports to path
+func WriteSarifReport(reports []types.Report, repoPath, path string) error {
+	data, err := json.MarshalIndent(NewSarifLog(reports, repoPath), "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, 0644)
+}
+
+// sarifURI returns the file relative to repoPath with forward slashes, files outside of it are kept absolute
+func sarifURI(file, repoPath string) string {
+	if repoPath != "" && isWithin(repoPath, file) {
+		if rel, err := filepath.Rel(repoPath, file); err == nil {
+			return filepath.ToSlash(rel)
+		}
+	}
+
+	return filepath.ToSlash(file)
+}
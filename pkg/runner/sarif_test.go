@@ -0,0 +1,67 @@
+package runner
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/onsi/ginkgo/v2/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSarif(t *testing.T) {
+	failed := newSpecReport([]string{"API"}, "returns 500", types.SpecStateFailed)
+	failed.Failure = types.Failure{
+		Message:  "Expected 200 to equal 500",
+		Location: types.CodeLocation{FileName: "/data/repo/e2e/url_test.go", LineNumber: 21},
+	}
+	reports := []types.Report{{
+		SuiteDescription: "E2E Suite",
+		SpecReports: types.SpecReports{
+			{
+				LeafNodeType: types.NodeTypeAfterSuite,
+				State:        types.SpecStatePanicked,
+				Failure: types.Failure{
+					Message:  "Test Panicked",
+					Location: types.CodeLocation{FileName: "/go/pkg/mod/example.com/db/db.go", LineNumber: 7},
+				},
+			},
+			newSpecReport([]string{"API"}, "responds", types.SpecStatePassed),
+			failed,
+		},
+	}}
+
+	t.Run("NewSarifLog should locate failures relative to the repository", func(t *testing.T) {
+		log := NewSarifLog(reports, "/data/repo")
+		assert.Equal(t, "2.1.0", log.Version)
+		assert.Len(t, log.Runs, 1)
+		assert.Equal(t, "ginkgo", log.Runs[0].Tool.Driver.Name)
+
+		results := log.Runs[0].Results
+		assert.Len(t, results, 2)
+		assert.Equal(t, sarifSuiteFailure, results[0].RuleID)
+		assert.Equal(t, "/go/pkg/mod/example.com/db/db.go", results[0].Locations[0].PhysicalLocation.ArtifactLocation.URI)
+
+		assert.Equal(t, SarifResult{
+			RuleID:  sarifSpecFailure,
+			Level:   "error",
+			Message: SarifMessage{Text: "E2E Suite - [It] API returns 500\nExpected 200 to equal 500"},
+			Locations: []SarifLocation{{PhysicalLocation: SarifPhysicalLocation{
+				ArtifactLocation: SarifArtifactLocation{URI: "e2e/url_test.go"},
+				Region:           SarifRegion{StartLine: 21},
+			}}},
+		}, results[1])
+	})
+
+	t.Run("WriteSarifReport should write the log as JSON", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "report.sarif")
+		assert.NoError(t, WriteSarifReport(reports, "/data/repo", path))
+
+		data, err := os.ReadFile(path)
+		assert.NoError(t, err)
+		log := map[string]interface{}{}
+		assert.NoError(t, json.Unmarshal(data, &log))
+		assert.Equal(t, sarifSchema, log["$schema"])
+	})
+}
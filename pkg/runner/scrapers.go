@@ -0,0 +1,273 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/kubeshop/testkube/pkg/envs"
+	"github.com/kubeshop/testkube/pkg/executor/output"
+	"github.com/kubeshop/testkube/pkg/executor/scraper"
+	"github.com/kubeshop/testkube/pkg/ui"
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+const (
+	// ScraperTypeEnv selects the scraper implementation: minio (default), filesystem or noop
+	ScraperTypeEnv = "RUNNER_SCRAPERTYPE"
+	// ScraperPathEnv is the directory artifacts are copied to by the filesystem scraper
+	ScraperPathEnv = "RUNNER_SCRAPERPATH"
+
+	ScraperTypeMinio      = "minio"
+	ScraperTypeFilesystem = "filesystem"
+	ScraperTypeNoop       = "noop"
+)
+
+// FileScraper uploads artifact files of an execution under their names. Unlike scraper.Scraper, which is given
+// directories, it keeps the layout of the artifacts, scrapers of the executor implement both.
+type FileScraper interface {
+	ScrapeFiles(executionID string, files []ArtifactFile) error
+}
+
+// ArtifactFetcher downloads an artifact of a previous execution, e.g. a report to rerun failed specs from
+type ArtifactFetcher interface {
+	FetchFile(executionID, name, destination string) error
+}
+
+// NewScraper returns the scraper selected by RUNNER_SCRAPERTYPE, MinIO is used when it's not set
+func NewScraper(params envs.Params) (scraper.Scraper, error) {
+	scraperType := os.Getenv(ScraperTypeEnv)
+	output.PrintLog(fmt.Sprintf("%s=\"%s\"", ScraperTypeEnv, scraperType))
+
+	switch scraperType {
+	case "", ScraperTypeMinio:
+		return NewMinioScraper(params), nil
+	case ScraperTypeFilesystem:
+		root := os.Getenv(ScraperPathEnv)
+		if root == "" {
+			return nil, fmt.Errorf("%s must be set for the %s scraper", ScraperPathEnv, ScraperTypeFilesystem)
+		}
+		return NewFilesystemScraper(root), nil
+	case ScraperTypeNoop:
+		return NoopScraper{}, nil
+	default:
+		return nil, fmt.Errorf("unknown scraper type %q, expected %s, %s or %s", scraperType, ScraperTypeMinio, ScraperTypeFilesystem, ScraperTypeNoop)
+	}
+}
+
+// NewMinioScraper returns a scraper uploading artifacts to the MinIO/S3 bucket of the executor
+func NewMinioScraper(params envs.Params) *MinioScraper {
+	return &MinioScraper{
+		MinioScraper: *scraper.NewMinioScraper(
+			params.Endpoint,
+			params.AccessKeyID,
+			params.SecretAccessKey,
+			params.Location,
+			params.Token,
+			params.Bucket,
+			params.Ssl,
+		),
+	}
+}
+
+// MinioScraper uploads artifacts to MinIO/S3, files of every execution are stored in a folder named after
+// the execution ID. Directories are scraped by the embedded Testkube scraper, files keep their names,
+// directories included.
+type MinioScraper struct {
+	scraper.MinioScraper
+}
+
+// ScrapeFiles uploads files under their names, the bucket is checked and created only once
+func (s MinioScraper) ScrapeFiles(executionID string, files []ArtifactFile) error {
+	output.PrintLog(fmt.Sprintf("%s Scraping %d artifacts", ui.IconCabinet, len(files)))
+	client, err := s.connect()
+	if err != nil {
+		output.PrintLog(fmt.Sprintf("%s Failed to scrape artifacts: %s", ui.IconCross, err.Error()))
+		return fmt.Errorf("error occured creating minio client: %w", err)
+	}
+
+	ctx := context.Background()
+	if err = s.ensureBucket(ctx, client); err != nil {
+		output.PrintLog(fmt.Sprintf("%s Failed to scrape artifacts: %s", ui.IconCross, err.Error()))
+		return err
+	}
+
+	for _, file := range files {
+		if err := s.upload(ctx, client, executionID, file); err != nil {
+			output.PrintLog(fmt.Sprintf("%s Failed to scrape artifacts: %s", ui.IconCross, err.Error()))
+			return fmt.Errorf("could not upload artifact %s: %w", file.Name, err)
+		}
+	}
+
+	output.PrintLog(fmt.Sprintf("%s Successfully scraped artifacts", ui.IconCheckMark))
+	return nil
+}
+
+// FetchFile downloads the artifact uploaded under name by the execution to destination
+func (s MinioScraper) FetchFile(executionID, name, destination string) error {
+	client, err := s.connect()
+	if err != nil {
+		return fmt.Errorf("error occured creating minio client: %w", err)
+	}
+
+	if err = client.FGetObject(context.Background(), s.Bucket, path.Join(executionID, name), destination, minio.GetObjectOptions{}); err != nil {
+		return fmt.Errorf("could not download artifact %s of execution %s: %w", name, executionID, err)
+	}
+
+	return nil
+}
+
+// connect creates a MinIO client with the credentials the Testkube storage client uses
+func (s MinioScraper) connect() (*minio.Client, error) {
+	creds := credentials.NewIAM("")
+	if s.AccessKeyID != "" && s.SecretAccessKey != "" {
+		creds = credentials.NewStaticV4(s.AccessKeyID, s.SecretAccessKey, s.Token)
+	}
+
+	return minio.New(s.Endpoint, &minio.Options{Creds: creds, Secure: s.Ssl, Region: s.Location})
+}
+
+func (s MinioScraper) ensureBucket(ctx context.Context, client *minio.Client) error {
+	exists, err := client.BucketExists(ctx, s.Bucket)
+	if err != nil {
+		return fmt.Errorf("error checking does bucket %s exists: %w", s.Bucket, err)
+	}
+
+	if !exists {
+		if err = client.MakeBucket(ctx, s.Bucket, minio.MakeBucketOptions{Region: s.Location}); err != nil {
+			return fmt.Errorf("error creating bucket %s: %w", s.Bucket, err)
+		}
+	}
+
+	return nil
+}
+
+func (s MinioScraper) upload(ctx context.Context, client *minio.Client, executionID string, file ArtifactFile) error {
+	object, err := os.Open(file.Source)
+	if err != nil {
+		return err
+	}
+	defer object.Close()
+
+	info, err := object.Stat()
+	if err != nil {
+		return err
+	}
+
+	_, err = client.PutObject(ctx, s.Bucket, path.Join(executionID, file.Name), object, info.Size(),
+		minio.PutObjectOptions{ContentType: "application/octet-stream"})
+	return err
+}
+
+// NewFilesystemScraper returns a scraper copying artifacts to root
+func NewFilesystemScraper(root string) *FilesystemScraper {
+	return &FilesystemScraper{Root: root}
+}
+
+// FilesystemScraper copies artifacts to a local or mounted directory, files of every execution
+// are stored in a subdirectory named after the execution ID
+type FilesystemScraper struct {
+	Root string
+}
+
+// Scrape copies files in directories named after their path relative to the directory
+func (s FilesystemScraper) Scrape(executionID string, directories []string) error {
+	files, err := directoryFiles(directories)
+	if err != nil {
+		return err
+	}
+
+	return s.ScrapeFiles(executionID, files)
+}
+
+// ScrapeFiles copies files under their names
+func (s FilesystemScraper) ScrapeFiles(executionID string, files []ArtifactFile) error {
+	output.PrintLog(fmt.Sprintf("%s Copying %d artifacts to %s", ui.IconCabinet, len(files), s.Root))
+	destination := filepath.Join(s.Root, executionID)
+	for _, file := range files {
+		if err := copyFile(file.Source, filepath.Join(destination, filepath.FromSlash(file.Name))); err != nil {
+			output.PrintLog(fmt.Sprintf("%s Failed to copy artifacts: %s", ui.IconCross, err.Error()))
+			return fmt.Errorf("could not copy artifact %s: %w", file.Source, err)
+		}
+	}
+
+	output.PrintLog(fmt.Sprintf("%s Successfully copied artifacts", ui.IconCheckMark))
+	return nil
+}
+
+// FetchFile copies the artifact copied under name by the execution to destination
+func (s FilesystemScraper) FetchFile(executionID, name, destination string) error {
+	if err := copyFile(filepath.Join(s.Root, executionID, filepath.FromSlash(name)), destination); err != nil {
+		return fmt.Errorf("could not copy artifact %s of execution %s: %w", name, executionID, err)
+	}
+
+	return nil
+}
+
+// NoopScraper drops all artifacts, for environments without artifact storage
+type NoopScraper struct{}
+
+// Scrape does nothing
+func (s NoopScraper) Scrape(executionID string, directories []string) error {
+	output.PrintLog(fmt.Sprintf("%s Skipping scraping of artifacts %s", ui.IconWarning, directories))
+	return nil
+}
+
+// ScrapeFiles does nothing
+func (s NoopScraper) ScrapeFiles(executionID string, files []ArtifactFile) error {
+	output.PrintLog(fmt.Sprintf("%s Skipping scraping of %d artifacts", ui.IconWarning, len(files)))
+	return nil
+}
+
+// directoryFiles lists files of directories named after their path relative to the directory
+func directoryFiles(directories []string) ([]ArtifactFile, error) {
+	sources := []ArtifactSource{}
+	for _, directory := range directories {
+		sources = append(sources, ArtifactSource{Path: directory})
+	}
+
+	return ArtifactFiles(sources)
+}
+
+// stageFiles copies files under their names into dir, so scrapers given directories get the artifacts layout
+func stageFiles(files []ArtifactFile, dir string) error {
+	if err := os.RemoveAll(dir); err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		if err := copyFile(file.Source, filepath.Join(dir, filepath.FromSlash(file.Name))); err != nil {
+			return fmt.Errorf("could not stage artifact %s: %w", file.Source, err)
+		}
+	}
+
+	return nil
+}
+
+func copyFile(source, destination string) error {
+	if err := os.MkdirAll(filepath.Dir(destination), os.ModePerm); err != nil {
+		return err
+	}
+
+	in, err := os.Open(source)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.Create(destination)
+	if err != nil {
+		return err
+	}
+
+	if _, err = io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+
+	return out.Close()
+}
@@ -0,0 +1,131 @@
+package runner
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/kubeshop/testkube/pkg/envs"
+	"github.com/kubeshop/testkube/pkg/executor/scraper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScrapers(t *testing.T) {
+	t.Run("NewScraper should select the scraper from the environment", func(t *testing.T) {
+		t.Setenv(ScraperTypeEnv, "")
+		s, err := NewScraper(envs.Params{})
+		assert.NoError(t, err)
+		assert.IsType(t, &MinioScraper{}, s)
+
+		t.Setenv(ScraperTypeEnv, ScraperTypeNoop)
+		s, err = NewScraper(envs.Params{})
+		assert.NoError(t, err)
+		assert.Equal(t, NoopScraper{}, s)
+
+		t.Setenv(ScraperTypeEnv, ScraperTypeFilesystem)
+		t.Setenv(ScraperPathEnv, "/mnt/artifacts")
+		s, err = NewScraper(envs.Params{})
+		assert.NoError(t, err)
+		assert.Equal(t, &FilesystemScraper{Root: "/mnt/artifacts"}, s)
+	})
+
+	t.Run("NewScraper should fail on unknown type or missing path", func(t *testing.T) {
+		t.Setenv(ScraperTypeEnv, ScraperTypeFilesystem)
+		t.Setenv(ScraperPathEnv, "")
+		_, err := NewScraper(envs.Params{})
+		assert.Error(t, err)
+
+		t.Setenv(ScraperTypeEnv, "gcs")
+		_, err = NewScraper(envs.Params{})
+		assert.Error(t, err)
+	})
+
+	t.Run("FilesystemScraper should copy files under their names into the execution directory", func(t *testing.T) {
+		source := t.TempDir()
+		root := t.TempDir()
+		assert.NoError(t, os.WriteFile(filepath.Join(source, "report.xml"), []byte("junit"), 0644))
+		assert.NoError(t, os.WriteFile(filepath.Join(source, "api.har"), []byte("har"), 0644))
+
+		err := NewFilesystemScraper(root).ScrapeFiles("64327a1f", []ArtifactFile{
+			{Source: filepath.Join(source, "report.xml"), Name: "report.xml"},
+			{Source: filepath.Join(source, "api.har"), Name: "files/logs/api.har"},
+		})
+		assert.NoError(t, err)
+
+		for file, content := range map[string]string{"report.xml": "junit", "files/logs/api.har": "har"} {
+			data, err := os.ReadFile(filepath.Join(root, "64327a1f", file))
+			assert.NoError(t, err)
+			assert.Equal(t, content, string(data))
+		}
+	})
+
+	t.Run("FilesystemScraper should keep the layout of scraped directories", func(t *testing.T) {
+		source := t.TempDir()
+		root := t.TempDir()
+		assert.NoError(t, os.MkdirAll(filepath.Join(source, "logs"), 0755))
+		assert.NoError(t, os.WriteFile(filepath.Join(source, "logs", "api.log"), []byte("log"), 0644))
+
+		var s scraper.Scraper = NewFilesystemScraper(root)
+		assert.NoError(t, s.Scrape("64327a1f", []string{source, filepath.Join(source, "missing")}))
+		assert.FileExists(t, filepath.Join(root, "64327a1f", "logs", "api.log"))
+	})
+
+	t.Run("MinioScraper should check the bucket once and upload files under their names", func(t *testing.T) {
+		var mu sync.Mutex
+		requests := []string{}
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			requests = append(requests, r.Method+" "+r.URL.Path)
+			mu.Unlock()
+			io.Copy(io.Discard, r.Body)
+			w.Header().Set("ETag", `"d41d8cd98f00b204e9800998ecf8427e"`)
+		}))
+		defer server.Close()
+
+		source := t.TempDir()
+		assert.NoError(t, os.WriteFile(filepath.Join(source, "report.xml"), []byte("junit"), 0644))
+		assert.NoError(t, os.WriteFile(filepath.Join(source, "api.har"), []byte("har"), 0644))
+
+		s := NewMinioScraper(envs.Params{Endpoint: strings.TrimPrefix(server.URL, "http://"), AccessKeyID: "key",
+			SecretAccessKey: "secret", Location: "us-east-1", Bucket: "testkube-artifacts"})
+		err := s.ScrapeFiles("64327a1f", []ArtifactFile{
+			{Source: filepath.Join(source, "report.xml"), Name: "report.xml"},
+			{Source: filepath.Join(source, "api.har"), Name: "files/logs/api.har"},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{
+			"HEAD /testkube-artifacts/",
+			"PUT /testkube-artifacts/64327a1f/report.xml",
+			"PUT /testkube-artifacts/64327a1f/files/logs/api.har",
+		}, requests)
+	})
+
+	t.Run("MinioScraper should download artifacts of previous executions", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/testkube-artifacts/64327a1f/report.json" {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Header().Set("ETag", `"d41d8cd98f00b204e9800998ecf8427e"`)
+			w.Header().Set("Last-Modified", "Mon, 12 Oct 2026 10:00:00 GMT")
+			w.Header().Set("Content-Length", "2")
+			if r.Method == http.MethodGet {
+				w.Write([]byte("[]"))
+			}
+		}))
+		defer server.Close()
+
+		s := NewMinioScraper(envs.Params{Endpoint: strings.TrimPrefix(server.URL, "http://"), AccessKeyID: "key",
+			SecretAccessKey: "secret", Location: "us-east-1", Bucket: "testkube-artifacts"})
+		destination := filepath.Join(t.TempDir(), "rerun", "report.json")
+		assert.NoError(t, s.FetchFile("64327a1f", "report.json", destination))
+		data, err := os.ReadFile(destination)
+		assert.NoError(t, err)
+		assert.Equal(t, "[]", string(data))
+	})
+}
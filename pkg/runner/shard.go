@@ -0,0 +1,170 @@
+package runner
+
+import (
+	"fmt"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/kubeshop/testkube/pkg/executor/output"
+	"github.com/kubeshop/testkube/pkg/process"
+	"github.com/kubeshop/testkube/pkg/ui"
+	"github.com/onsi/ginkgo/v2/types"
+)
+
+const (
+	shardByPackage = "package"
+	shardBySpec    = "spec"
+	shardSpecsFile = ".shard-specs.json"
+)
+
+// Shard is a slice of the suite run by a single execution
+type Shard struct {
+	Index int
+	Total int
+}
+
+// ParseShard reads zero based shard index and total number of shards
+func ParseShard(index, total string) (Shard, error) {
+	shard := Shard{}
+	var err error
+	if shard.Total, err = strconv.Atoi(total); err != nil || shard.Total < 1 {
+		return shard, fmt.Errorf("invalid shard total %q, expected a positive number", total)
+	}
+
+	if index == "" {
+		index = "0"
+	}
+	if shard.Index, err = strconv.Atoi(index); err != nil || shard.Index < 0 || shard.Index >= shard.Total {
+		return shard, fmt.Errorf("invalid shard index %q, expected a number from 0 to %d", index, shard.Total-1)
+	}
+
+	return shard, nil
+}
+
+// String returns 1 based shard position, e.g. "2/4"
+func (s Shard) String() string {
+	return fmt.Sprintf("%d/%d", s.Index+1, s.Total)
+}
+
+// Select returns the items assigned to the shard, items are sorted and dealt round robin
+// so every shard gets a deterministic and balanced slice
+func (s Shard) Select(items []string) []string {
+	sorted := append([]string{}, items...)
+	sort.Strings(sorted)
+
+	selected := []string{}
+	for i, item := range sorted {
+		if i%s.Total == s.Index {
+			selected = append(selected, item)
+		}
+	}
+
+	return selected
+}
+
+// FindSuitePackages returns directories below dir, relative to base, with test files importing Ginkgo v2
+func FindSuitePackages(dir, base string) ([]string, error) {
+	packages := map[string]bool{}
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			if path != dir && (d.Name() == "vendor" || d.Name() == "testdata" || strings.HasPrefix(d.Name(), ".")) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if !strings.HasSuffix(path, "_test.go") || packages[filepath.Dir(path)] {
+			return nil
+		}
+
+		file, err := parser.ParseFile(token.NewFileSet(), path, nil, parser.ImportsOnly)
+		if err != nil {
+			return nil
+		}
+
+		for _, spec := range file.Imports {
+			if importPath, err := strconv.Unquote(spec.Path.Value); err == nil && strings.HasPrefix(importPath, ginkgoModule) {
+				packages[filepath.Dir(path)] = true
+				break
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	relative := []string{}
+	for pkg := range packages {
+		rel, err := filepath.Rel(base, pkg)
+		if err != nil {
+			return nil, err
+		}
+		relative = append(relative, rel)
+	}
+
+	return relative, nil
+}
+
+// ListSpecs runs ginkgo in dry-run mode with the given params and returns the reports of all suites,
+// specs that will run are reported as passed while filtered out specs are skipped
+func ListSpecs(bin, path, runPath string, params map[string]string) ([]types.Report, error) {
+	output.PrintLog(fmt.Sprintf("%s Listing specs with ginkgo --dry-run", ui.IconWorld))
+
+	dryRunParams := make(map[string]string)
+	for k, v := range params {
+		dryRunParams[k] = v
+	}
+
+	// dry runs are serial only and don't need any other report or instrumentation
+	for _, k := range []string{"GinkgoParallel", "GinkgoParallelProcs", "GinkgoJunitReport", "GinkgoTeamCityReport",
+		"GinkgoCover", "GinkgoCoverProfile", "GinkgoRace", "GinkgoUntilItFails", "GinkgoRepeat"} {
+		delete(dryRunParams, k)
+	}
+	dryRunParams["GinkgoDryRun"] = "--dry-run"
+	dryRunParams["GinkgoJsonReport"] = "--json-report " + shardSpecsFile
+
+	args, err := BuildGinkgoArgs(dryRunParams, path, runPath)
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := process.ExecuteInDir(runPath, bin, args...)
+	reportPath := filepath.Join(runPath, shardSpecsFile)
+	defer os.Remove(reportPath)
+	if err != nil {
+		return nil, fmt.Errorf("could not list specs: %w\n%s", err, out)
+	}
+
+	return LoadGinkgoReports(reportPath)
+}
+
+// SpecsToRun returns focus texts of specs that will run according to a dry-run report
+func SpecsToRun(reports []types.Report) []string {
+	texts := []string{}
+	seen := make(map[string]bool)
+	for _, report := range reports {
+		for _, spec := range report.SpecReports {
+			text := FocusText(report.SuiteDescription, spec.FullText())
+			if spec.LeafNodeType != types.NodeTypeIt || spec.State != types.SpecStatePassed || seen[text] {
+				continue
+			}
+
+			seen[text] = true
+			texts = append(texts, text)
+		}
+	}
+
+	return texts
+}
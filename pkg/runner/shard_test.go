@@ -0,0 +1,122 @@
+package runner
+
+import (
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/onsi/ginkgo/v2/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShard(t *testing.T) {
+	t.Run("ParseShard should validate index and total", func(t *testing.T) {
+		shard, err := ParseShard("1", "4")
+		assert.NoError(t, err)
+		assert.Equal(t, Shard{Index: 1, Total: 4}, shard)
+		assert.Equal(t, "2/4", shard.String())
+
+		shard, err = ParseShard("", "2")
+		assert.NoError(t, err)
+		assert.Equal(t, 0, shard.Index)
+
+		_, err = ParseShard("4", "4")
+		assert.Error(t, err)
+		_, err = ParseShard("0", "zero")
+		assert.Error(t, err)
+	})
+
+	t.Run("Shard.Select should deal every item to exactly one shard", func(t *testing.T) {
+		items := []string{"e", "c", "a", "d", "b"}
+		selected := []string{}
+		for i := 0; i < 3; i++ {
+			selected = append(selected, Shard{Index: i, Total: 3}.Select(items)...)
+		}
+
+		assert.ElementsMatch(t, items, selected)
+		assert.Equal(t, []string{"a", "d"}, Shard{Index: 0, Total: 3}.Select(items))
+		assert.Equal(t, []string{"c"}, Shard{Index: 2, Total: 3}.Select(items))
+	})
+
+	t.Run("FindSuitePackages should find directories with Ginkgo v2 suites", func(t *testing.T) {
+		root := filepath.Join("..", "..")
+		packages, err := FindSuitePackages(filepath.Join(root, "examples"), root)
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, []string{"examples/e2e", "examples/other", "examples/testkube-api"}, packages)
+	})
+
+	t.Run("SpecsToRun should only return specs selected by the dry-run", func(t *testing.T) {
+		reports := []types.Report{{
+			SuiteDescription: "API Suite",
+			SpecReports: types.SpecReports{
+				{LeafNodeType: types.NodeTypeBeforeSuite, State: types.SpecStatePassed},
+				newSpecReport([]string{"API"}, "responds", types.SpecStatePassed),
+				newSpecReport([]string{"API"}, "is filtered out", types.SpecStateSkipped),
+			},
+		}}
+		assert.Equal(t, []string{"API Suite API responds"}, SpecsToRun(reports))
+	})
+
+	t.Run("Shard focus should select exactly the specs of the shard the way ginkgo matches the focus", func(t *testing.T) {
+		report := types.Report{
+			SuiteDescription: "API Suite",
+			SpecReports: types.SpecReports{
+				newSpecReport([]string{"API"}, "responds", types.SpecStatePassed),
+				newSpecReport([]string{"API"}, "responds (fast)", types.SpecStatePassed),
+				newSpecReport([]string{"API", "errors"}, "return 500", types.SpecStatePassed),
+			},
+		}
+
+		for i := 0; i < 2; i++ {
+			selected := Shard{Index: i, Total: 2}.Select(SpecsToRun([]types.Report{report}))
+			re := regexp.MustCompile(flagValue(RerunFocus(selected)))
+
+			// ginkgo matches the focus against the suite description and the spec text
+			matched := []string{}
+			for _, spec := range report.SpecReports {
+				if text := FocusText(report.SuiteDescription, spec.FullText()); re.MatchString(text) {
+					matched = append(matched, text)
+				}
+			}
+			assert.ElementsMatch(t, selected, matched)
+		}
+	})
+
+	t.Run("Shard focus should deal overlapping spec texts of several suites to exactly one shard", func(t *testing.T) {
+		reports := []types.Report{
+			{
+				SuiteDescription: "API Suite",
+				SpecReports: types.SpecReports{
+					newSpecReport(nil, "responds", types.SpecStatePassed),
+					newSpecReport([]string{"API"}, "responds", types.SpecStatePassed),
+					newSpecReport([]string{"Other API"}, "responds", types.SpecStatePassed),
+				},
+			},
+			{
+				SuiteDescription: "DB Suite",
+				SpecReports: types.SpecReports{
+					newSpecReport(nil, "responds", types.SpecStatePassed),
+					newSpecReport([]string{"API"}, "responds", types.SpecStatePassed),
+				},
+			},
+		}
+		assert.Len(t, SpecsToRun(reports), 5)
+
+		runs := make(map[string]int)
+		for i := 0; i < 3; i++ {
+			re := regexp.MustCompile(flagValue(RerunFocus(Shard{Index: i, Total: 3}.Select(SpecsToRun(reports)))))
+			for _, report := range reports {
+				for _, spec := range report.SpecReports {
+					if text := FocusText(report.SuiteDescription, spec.FullText()); re.MatchString(text) {
+						runs[text]++
+					}
+				}
+			}
+		}
+
+		assert.Len(t, runs, 5)
+		for text, count := range runs {
+			assert.Equal(t, 1, count, text)
+		}
+	})
+}
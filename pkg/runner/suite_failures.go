@@ -0,0 +1,105 @@
+package runner
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kubeshop/testkube/pkg/api/v1/testkube"
+	"github.com/kubeshop/testkube/pkg/executor/output"
+	"github.com/kubeshop/testkube/pkg/ui"
+	"github.com/onsi/ginkgo/v2/types"
+)
+
+// SuiteFailure is a failed suite level node, e.g. BeforeSuite or SynchronizedAfterSuite
+type SuiteFailure struct {
+	Step     string
+	Suite    string
+	NodeType string
+	Message  string
+	Location string
+	Duration string
+	// NotRun is the number of specs of the suite skipped because of the failure
+	NotRun int
+}
+
+// FindSuiteFailures returns failed suite level nodes of all reports
+func FindSuiteFailures(reports []types.Report) []SuiteFailure {
+	failures := []SuiteFailure{}
+	for _, report := range reports {
+		notRun := 0
+		for _, spec := range report.SpecReports {
+			if spec.LeafNodeType == types.NodeTypeIt && spec.State == types.SpecStateSkipped {
+				notRun++
+			}
+		}
+
+		for _, spec := range report.SpecReports {
+			if !spec.LeafNodeType.Is(types.NodeTypesForSuiteLevelNodes) || !spec.Failed() {
+				continue
+			}
+
+			failure := SuiteFailure{
+				Step:     SpecStepName(report, spec),
+				Suite:    report.SuiteDescription,
+				NodeType: spec.LeafNodeType.String(),
+				Message:  strings.TrimSpace(spec.Failure.Message),
+				Location: spec.Failure.Location.String(),
+				Duration: spec.RunTime.String(),
+			}
+			if spec.LeafNodeType.Is(types.NodeTypeBeforeSuite | types.NodeTypeSynchronizedBeforeSuite) {
+				failure.NotRun = notRun
+			}
+
+			failures = append(failures, failure)
+		}
+	}
+
+	return failures
+}
+
+// AttachSuiteFailures adds the failure message and location to the steps of failed suite level nodes,
+// nodes missing in the JUnit report get their own step
+func AttachSuiteFailures(result *testkube.ExecutionResult, failures []SuiteFailure) {
+	for _, failure := range failures {
+		step := FindStep(result, failure.Step)
+		if step == nil {
+			result.Steps = append(result.Steps, testkube.ExecutionStepResult{Name: failure.Step, Duration: failure.Duration})
+			step = &result.Steps[len(result.Steps)-1]
+		}
+
+		step.Status = string(testkube.FAILED_ExecutionStatus)
+		step.AssertionResults = append(step.AssertionResults, testkube.AssertionResult{
+			Name:         fmt.Sprintf("%s failed at %s", failure.NodeType, failure.Location),
+			Status:       string(testkube.FAILED_ExecutionStatus),
+			ErrorMessage: failure.Message,
+		})
+	}
+}
+
+// SuiteFailuresError returns an error naming failed suite level nodes, nil when there are none
+func SuiteFailuresError(failures []SuiteFailure) error {
+	if len(failures) == 0 {
+		return nil
+	}
+
+	messages := []string{}
+	for _, failure := range failures {
+		message := fmt.Sprintf("%s of %s failed at %s: %s", failure.NodeType, failure.Suite, failure.Location, firstLine(failure.Message))
+		if failure.NotRun > 0 {
+			message += fmt.Sprintf(" (%d specs not run)", failure.NotRun)
+		}
+		messages = append(messages, message)
+	}
+
+	output.PrintLog(fmt.Sprintf("%s %s", ui.IconCross, strings.Join(messages, "; ")))
+	return fmt.Errorf("%s", strings.Join(messages, "; "))
+}
+
+// firstLine returns the first line of a possibly multi line failure message
+func firstLine(message string) string {
+	if i := strings.Index(message, "\n"); i != -1 {
+		return message[:i]
+	}
+
+	return message
+}
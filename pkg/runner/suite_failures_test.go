@@ -0,0 +1,66 @@
+package runner
+
+import (
+	"testing"
+
+	"github.com/kubeshop/testkube/pkg/api/v1/testkube"
+	"github.com/onsi/ginkgo/v2/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSuiteFailures(t *testing.T) {
+	reports := []types.Report{{
+		SuiteDescription: "E2E Suite",
+		SpecReports: types.SpecReports{
+			{
+				LeafNodeType: types.NodeTypeSynchronizedBeforeSuite,
+				State:        types.SpecStateFailed,
+				Failure: types.Failure{
+					Message:  "database not reachable\nconnection refused",
+					Location: types.CodeLocation{FileName: "/data/repo/e2e/suite_test.go", LineNumber: 21},
+				},
+			},
+			newSpecReport([]string{"API"}, "responds", types.SpecStateSkipped),
+			newSpecReport([]string{"API"}, "is pending", types.SpecStatePending),
+			{LeafNodeType: types.NodeTypeAfterSuite, State: types.SpecStatePassed},
+		},
+	}}
+
+	t.Run("FindSuiteFailures should return failed suite level nodes with specs not run", func(t *testing.T) {
+		failures := FindSuiteFailures(reports)
+		assert.Equal(t, []SuiteFailure{{
+			Step:     "E2E Suite - [SynchronizedBeforeSuite]",
+			Suite:    "E2E Suite",
+			NodeType: "SynchronizedBeforeSuite",
+			Message:  "database not reachable\nconnection refused",
+			Location: "/data/repo/e2e/suite_test.go:21",
+			Duration: "0s",
+			NotRun:   1,
+		}}, failures)
+	})
+
+	t.Run("AttachSuiteFailures should add failure to existing or new steps", func(t *testing.T) {
+		result := testkube.ExecutionResult{Steps: []testkube.ExecutionStepResult{
+			{Name: "E2E Suite - [SynchronizedBeforeSuite]", Status: "failed"},
+		}}
+		failures := FindSuiteFailures(reports)
+		failures = append(failures, SuiteFailure{Step: "Other Suite - [AfterSuite]", NodeType: "AfterSuite", Location: "other_test.go:9", Message: "boom"})
+
+		AttachSuiteFailures(&result, failures)
+		assert.Len(t, result.Steps, 2)
+		assert.Equal(t, []testkube.AssertionResult{{
+			Name:         "SynchronizedBeforeSuite failed at /data/repo/e2e/suite_test.go:21",
+			Status:       "failed",
+			ErrorMessage: "database not reachable\nconnection refused",
+		}}, result.Steps[0].AssertionResults)
+		assert.Equal(t, "Other Suite - [AfterSuite]", result.Steps[1].Name)
+		assert.Equal(t, "failed", result.Steps[1].Status)
+	})
+
+	t.Run("SuiteFailuresError should name the failed node", func(t *testing.T) {
+		assert.NoError(t, SuiteFailuresError(nil))
+
+		err := SuiteFailuresError(FindSuiteFailures(reports))
+		assert.EqualError(t, err, "SynchronizedBeforeSuite of E2E Suite failed at /data/repo/e2e/suite_test.go:21: database not reachable (1 specs not run)")
+	})
+}
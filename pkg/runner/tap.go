@@ -0,0 +1,69 @@
+package runner
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/onsi/ginkgo/v2/types"
+)
+
+// RenderTAP renders specs and failed suite level nodes as a TAP version 13 stream, failures come
+// with a YAML block holding message and location, skipped specs are marked SKIP and pending ones TODO
+func RenderTAP(reports []types.Report) string {
+	lines := []string{}
+	for _, report := range reports {
+		for _, spec := range report.SpecReports {
+			if spec.LeafNodeType != types.NodeTypeIt && !spec.Failed() {
+				continue
+			}
+
+			number := len(lines) + 1
+			description := tapEscape(SpecStepName(report, spec))
+			switch {
+			case spec.Failed():
+				lines = append(lines, fmt.Sprintf("not ok %d - %s\n  ---\n  message: %s\n  severity: %s\n  at: %s\n  duration_ms: %d\n  ...",
+					number, description, tapYAMLString(spec.Failure.Message), spec.State, spec.Failure.Location, spec.RunTime.Milliseconds()))
+			case spec.State == types.SpecStateSkipped:
+				lines = append(lines, fmt.Sprintf("ok %d - %s # SKIP %s", number, description, tapEscape(skipReason(spec))))
+			case spec.State == types.SpecStatePending:
+				lines = append(lines, fmt.Sprintf("not ok %d - %s # TODO pending", number, description))
+			default:
+				lines = append(lines, fmt.Sprintf("ok %d - %s", number, description))
+			}
+		}
+	}
+
+	return fmt.Sprintf("TAP version 13\n1..%d\n%s", len(lines), strings.Join(append(lines, ""), "\n"))
+}
+
+// WriteTAPReport writes the TAP stream of the ginkgo reports to path
+func WriteTAPReport(reports []types.Report, path string) error {
+	return os.WriteFile(path, []byte(RenderTAP(reports)), 0644)
+}
+
+// skipReason returns the message of Skip() or "skipped" for specs filtered out
+func skipReason(spec types.SpecReport) string {
+	if message := firstLine(strings.TrimSpace(spec.Failure.Message)); message != "" {
+		return message
+	}
+
+	return "skipped"
+}
+
+// tapEscape escapes characters with a meaning in TAP test lines
+func tapEscape(text string) string {
+	text = strings.ReplaceAll(text, `\`, `\\`)
+	text = strings.ReplaceAll(text, "#", `\#`)
+	return strings.ReplaceAll(text, "\n", " ")
+}
+
+// tapYAMLString renders text as a YAML literal block in the TAP diagnostics
+func tapYAMLString(text string) string {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return "''"
+	}
+
+	return "|-\n    " + strings.ReplaceAll(text, "\n", "\n    ")
+}
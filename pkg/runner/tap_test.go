@@ -0,0 +1,59 @@
+package runner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/onsi/ginkgo/v2/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTAP(t *testing.T) {
+	failed := newSpecReport([]string{"API"}, "returns #500", types.SpecStateFailed)
+	failed.Failure = types.Failure{
+		Message:  "Expected\n    200\nto equal 500",
+		Location: types.CodeLocation{FileName: "/data/repo/e2e/url_test.go", LineNumber: 21},
+	}
+	skipped := newSpecReport([]string{"API"}, "needs a token", types.SpecStateSkipped)
+	skipped.Failure = types.Failure{Message: "no token configured"}
+
+	reports := []types.Report{{
+		SuiteDescription: "E2E Suite",
+		SpecReports: types.SpecReports{
+			{LeafNodeType: types.NodeTypeBeforeSuite, State: types.SpecStatePassed},
+			newSpecReport([]string{"API"}, "responds", types.SpecStatePassed),
+			failed,
+			skipped,
+			newSpecReport([]string{"API"}, "is pending", types.SpecStatePending),
+		},
+	}}
+
+	t.Run("RenderTAP should render a TAP version 13 stream", func(t *testing.T) {
+		assert.Equal(t, "TAP version 13\n"+
+			"1..4\n"+
+			"ok 1 - E2E Suite - [It] API responds\n"+
+			"not ok 2 - E2E Suite - [It] API returns \\#500\n"+
+			"  ---\n"+
+			"  message: |-\n    Expected\n        200\n    to equal 500\n"+
+			"  severity: failed\n"+
+			"  at: /data/repo/e2e/url_test.go:21\n"+
+			"  duration_ms: 2000\n"+
+			"  ...\n"+
+			"ok 3 - E2E Suite - [It] API needs a token # SKIP no token configured\n"+
+			"not ok 4 - E2E Suite - [It] API is pending # TODO pending\n", RenderTAP(reports))
+	})
+
+	t.Run("RenderTAP should render an empty plan without specs", func(t *testing.T) {
+		assert.Equal(t, "TAP version 13\n1..0\n", RenderTAP(nil))
+	})
+
+	t.Run("WriteTAPReport should write the stream to a file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "report.tap")
+		assert.NoError(t, WriteTAPReport(reports, path))
+
+		data, err := os.ReadFile(path)
+		assert.NoError(t, err)
+		assert.Equal(t, RenderTAP(reports), string(data))
+	})
+}
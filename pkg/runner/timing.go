@@ -0,0 +1,156 @@
+package runner
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/onsi/ginkgo/v2/types"
+)
+
+const (
+	specTimingsReport  = "spec-timings.json"
+	slowestSpecsReport = "slowest-specs.json"
+)
+
+var (
+	setupNodeTypes    = types.NodeTypeBeforeEach | types.NodeTypeJustBeforeEach | types.NodeTypeBeforeAll | types.NodeTypeBeforeSuite | types.NodeTypeSynchronizedBeforeSuite
+	teardownNodeTypes = types.NodeTypeAfterEach | types.NodeTypeJustAfterEach | types.NodeTypeAfterAll | types.NodeTypeCleanupAfterEach | types.NodeTypeCleanupAfterAll
+)
+
+// SpecTiming is the timeline of a single spec split into setup, It body and teardown
+type SpecTiming struct {
+	Step     string
+	Start    time.Time
+	End      time.Time
+	Duration time.Duration
+	Setup    time.Duration
+	Body     time.Duration
+	Teardown time.Duration
+}
+
+// MarshalJSON writes durations in seconds
+func (t SpecTiming) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Step     string    `json:"step"`
+		Start    time.Time `json:"start"`
+		End      time.Time `json:"end"`
+		Duration float64   `json:"duration"`
+		Setup    float64   `json:"setup"`
+		Body     float64   `json:"body"`
+		Teardown float64   `json:"teardown"`
+	}{t.Step, t.Start, t.End, t.Duration.Seconds(), t.Setup.Seconds(), t.Body.Seconds(), t.Teardown.Seconds()})
+}
+
+// SpecTimings returns timings of specs and suite level nodes which ran, ordered by start time. Time spent in
+// nodes is taken from the node spec events of the report, suite level nodes are accounted as setup or teardown.
+func SpecTimings(reports []types.Report) []SpecTiming {
+	timings := []SpecTiming{}
+	for _, report := range reports {
+		for _, spec := range report.SpecReports {
+			if spec.State.Is(types.SpecStateSkipped|types.SpecStatePending) || spec.StartTime.IsZero() {
+				continue
+			}
+
+			timing := SpecTiming{
+				Step:     SpecStepName(report, spec),
+				Start:    spec.StartTime,
+				End:      spec.EndTime,
+				Duration: spec.RunTime,
+			}
+
+			if spec.LeafNodeType.Is(types.NodeTypesForSuiteLevelNodes) {
+				if spec.LeafNodeType.Is(setupNodeTypes) {
+					timing.Setup = spec.RunTime
+				} else {
+					timing.Teardown = spec.RunTime
+				}
+				timings = append(timings, timing)
+				continue
+			}
+
+			bodyFromEvents := false
+			for _, event := range spec.SpecEvents {
+				if event.SpecEventType != types.SpecEventNodeEnd {
+					continue
+				}
+
+				switch {
+				case event.NodeType.Is(setupNodeTypes):
+					timing.Setup += event.Duration
+				case event.NodeType.Is(teardownNodeTypes):
+					timing.Teardown += event.Duration
+				case event.NodeType == types.NodeTypeIt:
+					timing.Body += event.Duration
+					bodyFromEvents = true
+				}
+			}
+
+			// reports without node events only know the total run time
+			if !bodyFromEvents {
+				timing.Body = spec.RunTime - timing.Setup - timing.Teardown
+			}
+
+			timings = append(timings, timing)
+		}
+	}
+
+	sort.SliceStable(timings, func(i, j int) bool {
+		return timings[i].Start.Before(timings[j].Start)
+	})
+
+	return timings
+}
+
+// SlowestSpecs returns at most n timings with the longest duration
+func SlowestSpecs(timings []SpecTiming, n int) []SpecTiming {
+	slowest := append([]SpecTiming{}, timings...)
+	sort.SliceStable(slowest, func(i, j int) bool {
+		return slowest[i].Duration > slowest[j].Duration
+	})
+
+	if len(slowest) > n {
+		slowest = slowest[:n]
+	}
+
+	return slowest
+}
+
+// ParseSlowestSpecs reads the number of slowest specs to report, empty value disables the summary
+func ParseSlowestSpecs(value string) (int, error) {
+	if value == "" {
+		return 0, nil
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid number of slowest specs %q", value)
+	}
+
+	return n, nil
+}
+
+// SlowestSpecsSummary renders slowest specs as appended to the execution output
+func SlowestSpecsSummary(slowest []SpecTiming) string {
+	b := strings.Builder{}
+	b.WriteString(fmt.Sprintf("Slowest specs: %d\n", len(slowest)))
+	for _, timing := range slowest {
+		b.WriteString(fmt.Sprintf("  %s: %s (setup %s, body %s, teardown %s)\n", timing.Step, timing.Duration, timing.Setup, timing.Body, timing.Teardown))
+	}
+
+	return b.String()
+}
+
+// WriteSpecTimings writes timings as a JSON artifact, all of them or the slowest ones
+func WriteSpecTimings(timings []SpecTiming, path string) error {
+	data, err := json.MarshalIndent(timings, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, 0644)
+}
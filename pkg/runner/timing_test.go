@@ -0,0 +1,100 @@
+package runner
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/onsi/ginkgo/v2/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTiming(t *testing.T) {
+	start := time.Date(2023, 4, 1, 10, 0, 0, 0, time.UTC)
+	withEvents := newSpecReport([]string{"API"}, "responds", types.SpecStatePassed)
+	withEvents.StartTime = start.Add(time.Second)
+	withEvents.RunTime = 5 * time.Second
+	withEvents.SpecEvents = types.SpecEvents{
+		{SpecEventType: types.SpecEventNodeStart, NodeType: types.NodeTypeBeforeEach},
+		{SpecEventType: types.SpecEventNodeEnd, NodeType: types.NodeTypeBeforeEach, Duration: time.Second},
+		{SpecEventType: types.SpecEventNodeEnd, NodeType: types.NodeTypeJustBeforeEach, Duration: time.Second},
+		{SpecEventType: types.SpecEventNodeEnd, NodeType: types.NodeTypeIt, Duration: 2 * time.Second},
+		{SpecEventType: types.SpecEventNodeEnd, NodeType: types.NodeTypeAfterEach, Duration: time.Second},
+	}
+	withoutEvents := newSpecReport([]string{"API"}, "has no events", types.SpecStateFailed)
+	withoutEvents.StartTime = start.Add(10 * time.Second)
+
+	reports := []types.Report{{
+		SuiteDescription: "E2E Suite",
+		SpecReports: types.SpecReports{
+			withEvents,
+			{LeafNodeType: types.NodeTypeBeforeSuite, State: types.SpecStatePassed, StartTime: start, RunTime: 3 * time.Second},
+			newSpecReport([]string{"API"}, "is skipped", types.SpecStateSkipped),
+			withoutEvents,
+		},
+	}}
+
+	t.Run("SpecTimings should split specs into setup, body and teardown", func(t *testing.T) {
+		timings := SpecTimings(reports)
+		assert.Len(t, timings, 3)
+
+		assert.Equal(t, "E2E Suite - [BeforeSuite]", timings[0].Step)
+		assert.Equal(t, 3*time.Second, timings[0].Setup)
+
+		assert.Equal(t, "E2E Suite - [It] API responds", timings[1].Step)
+		assert.Equal(t, start.Add(time.Second), timings[1].Start)
+		assert.Equal(t, 2*time.Second, timings[1].Setup)
+		assert.Equal(t, 2*time.Second, timings[1].Body)
+		assert.Equal(t, time.Second, timings[1].Teardown)
+
+		assert.Equal(t, "E2E Suite - [It] API has no events", timings[2].Step)
+		assert.Equal(t, 2*time.Second, timings[2].Body)
+	})
+
+	t.Run("SlowestSpecs should return the longest specs first", func(t *testing.T) {
+		slowest := SlowestSpecs(SpecTimings(reports), 2)
+		assert.Len(t, slowest, 2)
+		assert.Equal(t, "E2E Suite - [It] API responds", slowest[0].Step)
+		assert.Equal(t, "E2E Suite - [BeforeSuite]", slowest[1].Step)
+
+		assert.Contains(t, SlowestSpecsSummary(slowest), "E2E Suite - [It] API responds: 5s (setup 2s, body 2s, teardown 1s)\n")
+	})
+
+	t.Run("ParseSlowestSpecs should reject invalid numbers", func(t *testing.T) {
+		n, err := ParseSlowestSpecs("")
+		assert.NoError(t, err)
+		assert.Equal(t, 0, n)
+
+		_, err = ParseSlowestSpecs("-1")
+		assert.Error(t, err)
+	})
+
+	t.Run("WriteSpecTimings should write durations in seconds", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), specTimingsReport)
+		assert.NoError(t, WriteSpecTimings(SpecTimings(reports)[1:2], path))
+
+		data, err := os.ReadFile(path)
+		assert.NoError(t, err)
+
+		timings := []map[string]interface{}{}
+		assert.NoError(t, json.Unmarshal(data, &timings))
+		assert.Equal(t, "E2E Suite - [It] API responds", timings[0]["step"])
+		assert.Equal(t, 5.0, timings[0]["duration"])
+		assert.Equal(t, 2.0, timings[0]["body"])
+	})
+
+	t.Run("WriteSpecTimings should write the slowest specs longest first", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), slowestSpecsReport)
+		assert.NoError(t, WriteSpecTimings(SlowestSpecs(SpecTimings(reports), 2), path))
+
+		data, err := os.ReadFile(path)
+		assert.NoError(t, err)
+
+		timings := []map[string]interface{}{}
+		assert.NoError(t, json.Unmarshal(data, &timings))
+		assert.Len(t, timings, 2)
+		assert.GreaterOrEqual(t, timings[0]["duration"], timings[1]["duration"])
+	})
+}
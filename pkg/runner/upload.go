@@ -0,0 +1,282 @@
+package runner
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/kubeshop/testkube/pkg/executor/output"
+	"github.com/kubeshop/testkube/pkg/ui"
+)
+
+const (
+	artifactsManifest = "manifest.json"
+	artifactsArchive  = "artifacts.tar.gz"
+)
+
+// UploadOptions configure how scraped artifacts are packaged
+type UploadOptions struct {
+	Archive      bool
+	MaxFileSize  int64
+	MaxTotalSize int64
+}
+
+// Staged returns true when artifacts need to be packaged before scraping
+func (o UploadOptions) Staged() bool {
+	return o.Archive || o.MaxFileSize > 0 || o.MaxTotalSize > 0
+}
+
+// ManifestEntry is a single scraped file
+type ManifestEntry struct {
+	Path   string `json:"path"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+	// Skipped is the reason the file was not uploaded
+	Skipped string `json:"skipped,omitempty"`
+
+	source string
+}
+
+// Manifest lists all scraped files with their size and checksum
+type Manifest struct {
+	Files     []ManifestEntry `json:"files"`
+	TotalSize int64           `json:"totalSize"`
+	Archive   string          `json:"archive,omitempty"`
+}
+
+// ParseUploadOptions reads GinkgoArtifactsArchive, GinkgoArtifactMaxFileSize and GinkgoArtifactMaxTotalSize params
+func ParseUploadOptions(params map[string]string) (UploadOptions, error) {
+	options := UploadOptions{Archive: params["GinkgoArtifactsArchive"] == "true"}
+	var fileErr, totalErr error
+	if options.MaxFileSize, fileErr = ParseSize(params["GinkgoArtifactMaxFileSize"]); fileErr != nil {
+		fileErr = fmt.Errorf("artifact file size limit: %w", fileErr)
+	}
+	if options.MaxTotalSize, totalErr = ParseSize(params["GinkgoArtifactMaxTotalSize"]); totalErr != nil {
+		totalErr = fmt.Errorf("artifact total size limit: %w", totalErr)
+	}
+
+	return options, combineErrors(fileErr, totalErr)
+}
+
+// ParseSize reads sizes like 500KB, 10MB or 1GB, units are powers of 1024, empty size is 0
+func ParseSize(size string) (int64, error) {
+	value := strings.ToUpper(strings.TrimSpace(size))
+	if value == "" {
+		return 0, nil
+	}
+
+	multiplier := int64(1)
+	for _, unit := range []struct {
+		suffix     string
+		multiplier int64
+	}{{"GB", 1 << 30}, {"MB", 1 << 20}, {"KB", 1 << 10}, {"B", 1}} {
+		if strings.HasSuffix(value, unit.suffix) {
+			value = strings.TrimSpace(strings.TrimSuffix(value, unit.suffix))
+			multiplier = unit.multiplier
+			break
+		}
+	}
+
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid size %q", size)
+	}
+
+	return n * multiplier, nil
+}
+
+// BuildManifest lists files with the names they're uploaded under, their size and checksum.
+// Files over the size limits are marked as skipped and a warning is printed.
+func BuildManifest(files []ArtifactFile, options UploadOptions) (Manifest, error) {
+	manifest := Manifest{Files: []ManifestEntry{}}
+	for _, file := range files {
+		entry, err := newManifestEntry(file.Source, file.Name)
+		if err != nil {
+			return manifest, fmt.Errorf("could not read artifact %s: %w", file.Source, err)
+		}
+
+		switch {
+		case options.MaxFileSize > 0 && entry.Size > options.MaxFileSize:
+			entry.Skipped = fmt.Sprintf("exceeds file size limit of %d bytes", options.MaxFileSize)
+		case options.MaxTotalSize > 0 && manifest.TotalSize+entry.Size > options.MaxTotalSize:
+			entry.Skipped = fmt.Sprintf("exceeds total size limit of %d bytes", options.MaxTotalSize)
+		default:
+			manifest.TotalSize += entry.Size
+		}
+
+		if entry.Skipped != "" {
+			output.PrintLog(fmt.Sprintf("%s artifact %s (%d bytes) not uploaded: %s", ui.IconWarning, entry.Path, entry.Size, entry.Skipped))
+		}
+
+		manifest.Files = append(manifest.Files, entry)
+	}
+
+	return manifest, nil
+}
+
+// WriteManifest writes the manifest as JSON
+func WriteManifest(manifest Manifest, path string) error {
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, 0644)
+}
+
+// PrepareUpload writes the manifest of artifacts in sources and returns the files to scrape. Without
+// archive and limits the manifest is added to reports and sources are scraped as they are, otherwise files
+// within limits are copied or archived into stagingPath along with the manifest.
+func PrepareUpload(sources []ArtifactSource, reportsPath, stagingPath string, options UploadOptions) ([]ArtifactFile, error) {
+	files, err := ArtifactFiles(sources)
+	if err != nil {
+		return nil, err
+	}
+
+	if !options.Staged() {
+		// nothing was fetched yet, there's no place for the manifest
+		if reportsPath == "" {
+			return files, nil
+		}
+
+		// the manifest of a previous scraping is replaced
+		manifestPath := filepath.Join(reportsPath, artifactsManifest)
+		listed := []ArtifactFile{}
+		for _, file := range files {
+			if file.Source != manifestPath {
+				listed = append(listed, file)
+			}
+		}
+
+		manifest, err := BuildManifest(listed, options)
+		if err != nil {
+			return nil, err
+		}
+
+		if err = WriteManifest(manifest, manifestPath); err != nil {
+			return nil, err
+		}
+
+		return ArtifactFiles(sources)
+	}
+
+	manifest, err := BuildManifest(files, options)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = os.MkdirAll(stagingPath, os.ModePerm); err != nil {
+		return nil, err
+	}
+
+	if options.Archive {
+		manifest.Archive = artifactsArchive
+		err = writeArchive(manifest, filepath.Join(stagingPath, artifactsArchive))
+	} else {
+		err = copyManifestFiles(manifest, stagingPath)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not package artifacts: %w", err)
+	}
+
+	if err = WriteManifest(manifest, filepath.Join(stagingPath, artifactsManifest)); err != nil {
+		return nil, err
+	}
+
+	output.PrintLog(fmt.Sprintf("%s Packaged %d bytes of artifacts", ui.IconCheckMark, manifest.TotalSize))
+	return ArtifactFiles([]ArtifactSource{{Path: stagingPath}})
+}
+
+func newManifestEntry(source, name string) (ManifestEntry, error) {
+	file, err := os.Open(source)
+	if err != nil {
+		return ManifestEntry{}, err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	size, err := io.Copy(hash, file)
+	if err != nil {
+		return ManifestEntry{}, err
+	}
+
+	return ManifestEntry{Path: name, Size: size, SHA256: hex.EncodeToString(hash.Sum(nil)), source: source}, nil
+}
+
+func copyManifestFiles(manifest Manifest, destination string) error {
+	for _, entry := range manifest.Files {
+		if entry.Skipped != "" {
+			continue
+		}
+
+		if err := copyFile(entry.source, filepath.Join(destination, filepath.FromSlash(entry.Path))); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func writeArchive(manifest Manifest, path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	gz := gzip.NewWriter(file)
+	tw := tar.NewWriter(gz)
+	for _, entry := range manifest.Files {
+		if entry.Skipped != "" {
+			continue
+		}
+
+		if err = addToArchive(tw, entry); err != nil {
+			return err
+		}
+	}
+
+	if err = tw.Close(); err != nil {
+		return err
+	}
+	if err = gz.Close(); err != nil {
+		return err
+	}
+
+	return file.Close()
+}
+
+func addToArchive(tw *tar.Writer, entry ManifestEntry) error {
+	source, err := os.Open(entry.source)
+	if err != nil {
+		return err
+	}
+	defer source.Close()
+
+	info, err := source.Stat()
+	if err != nil {
+		return err
+	}
+
+	header, err := tar.FileInfoHeader(info, "")
+	if err != nil {
+		return err
+	}
+	header.Name = entry.Path
+	header.Size = entry.Size
+
+	if err = tw.WriteHeader(header); err != nil {
+		return err
+	}
+
+	_, err = io.CopyN(tw, source, entry.Size)
+	return err
+}
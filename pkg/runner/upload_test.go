@@ -0,0 +1,110 @@
+package runner
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpload(t *testing.T) {
+	newArtifacts := func(t *testing.T) (string, []ArtifactSource) {
+		dir := t.TempDir()
+		reportsPath := filepath.Join(dir, "reports")
+		artifactsPath := filepath.Join(dir, "artifacts")
+		assert.NoError(t, os.MkdirAll(reportsPath, 0755))
+		assert.NoError(t, os.MkdirAll(filepath.Join(artifactsPath, "spec"), 0755))
+		assert.NoError(t, os.WriteFile(filepath.Join(reportsPath, "report.xml"), []byte("junit"), 0644))
+		assert.NoError(t, os.WriteFile(filepath.Join(artifactsPath, "spec", "dump.log"), []byte(strings.Repeat("x", 2048)), 0644))
+		return reportsPath, []ArtifactSource{{Path: reportsPath, Name: reportsArtifactsName}, {Path: artifactsPath, Name: specArtifactsName}}
+	}
+
+	t.Run("ParseSize should read sizes with units", func(t *testing.T) {
+		for size, expected := range map[string]int64{"": 0, "512": 512, "10B": 10, "2KB": 2048, "10mb": 10 << 20, "1 GB": 1 << 30} {
+			n, err := ParseSize(size)
+			assert.NoError(t, err)
+			assert.Equal(t, expected, n, size)
+		}
+
+		_, err := ParseSize("ten MB")
+		assert.Error(t, err)
+	})
+
+	t.Run("BuildManifest should list files with checksums and skip files over limits", func(t *testing.T) {
+		_, sources := newArtifacts(t)
+		files, err := ArtifactFiles(sources)
+		assert.NoError(t, err)
+		manifest, err := BuildManifest(files, UploadOptions{MaxFileSize: 1024})
+		assert.NoError(t, err)
+		assert.Len(t, manifest.Files, 2)
+		assert.Equal(t, "report.xml", manifest.Files[0].Path)
+		assert.Equal(t, int64(5), manifest.Files[0].Size)
+		assert.Equal(t, "018bb207e5cea3036e36872e61a3a9d432d23b4a199661e7494f0f0607e57951", manifest.Files[0].SHA256)
+		assert.Empty(t, manifest.Files[0].Skipped)
+		assert.Equal(t, "artifacts/spec/dump.log", manifest.Files[1].Path)
+		assert.Equal(t, "exceeds file size limit of 1024 bytes", manifest.Files[1].Skipped)
+		assert.Equal(t, int64(5), manifest.TotalSize)
+	})
+
+	t.Run("PrepareUpload should add the manifest to reports when nothing is staged", func(t *testing.T) {
+		reportsPath, sources := newArtifacts(t)
+		files, err := PrepareUpload(sources, reportsPath, filepath.Join(t.TempDir(), "upload"), UploadOptions{})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"manifest.json", "report.xml", "artifacts/spec/dump.log"}, artifactNames(files))
+
+		data, err := os.ReadFile(filepath.Join(reportsPath, artifactsManifest))
+		assert.NoError(t, err)
+		manifest := Manifest{}
+		assert.NoError(t, json.Unmarshal(data, &manifest))
+		assert.Len(t, manifest.Files, 2)
+
+		// the manifest lists files under the names they're uploaded under
+		assert.Equal(t, "report.xml", manifest.Files[0].Path)
+		assert.Equal(t, "artifacts/spec/dump.log", manifest.Files[1].Path)
+	})
+
+	t.Run("PrepareUpload should stage files within the total size limit", func(t *testing.T) {
+		reportsPath, sources := newArtifacts(t)
+		stagingPath := filepath.Join(t.TempDir(), "upload")
+		files, err := PrepareUpload(sources, reportsPath, stagingPath, UploadOptions{MaxTotalSize: 1024})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"manifest.json", "report.xml"}, artifactNames(files))
+		assert.FileExists(t, filepath.Join(stagingPath, "report.xml"))
+		assert.NoFileExists(t, filepath.Join(stagingPath, "artifacts", "spec", "dump.log"))
+		assert.FileExists(t, filepath.Join(stagingPath, artifactsManifest))
+	})
+
+	t.Run("PrepareUpload should archive all files", func(t *testing.T) {
+		reportsPath, sources := newArtifacts(t)
+		stagingPath := filepath.Join(t.TempDir(), "upload")
+		_, err := PrepareUpload(sources, reportsPath, stagingPath, UploadOptions{Archive: true})
+		assert.NoError(t, err)
+
+		file, err := os.Open(filepath.Join(stagingPath, artifactsArchive))
+		assert.NoError(t, err)
+		defer file.Close()
+		gz, err := gzip.NewReader(file)
+		assert.NoError(t, err)
+
+		names := []string{}
+		tr := tar.NewReader(gz)
+		for header, err := tr.Next(); err == nil; header, err = tr.Next() {
+			names = append(names, header.Name)
+		}
+		assert.Equal(t, []string{"report.xml", "artifacts/spec/dump.log"}, names)
+	})
+}
+
+func artifactNames(files []ArtifactFile) []string {
+	names := []string{}
+	for _, file := range files {
+		names = append(names, file.Name)
+	}
+
+	return names
+}